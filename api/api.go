@@ -0,0 +1,65 @@
+// Package api contains the requests and responses of the Battlesnake API. It
+// has no side effects, so that tools working with recorded requests can use
+// it without starting or configuring a server.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+)
+
+type Game struct {
+	ID      string       `json:"id"`
+	Ruleset game.Ruleset `json:"ruleset"`
+	Map     string       `json:"map"`
+	Timeout int32        `json:"timeout"`
+	Source  string       `json:"source"`
+}
+
+type BattlesnakeInfoResponse struct {
+	APIVersion string `json:"apiversion"`
+	Author     string `json:"author"`
+	Color      string `json:"color"`
+	Head       string `json:"head"`
+	Tail       string `json:"tail"`
+}
+
+type GameRequest struct {
+	Game  Game             `json:"game"`
+	Turn  int              `json:"turn"`
+	Board game.Board       `json:"board"`
+	You   game.Battlesnake `json:"you"`
+}
+
+type MoveResponse struct {
+	Move  game.SnakeDirectionType `json:"move"`
+	Shout string                  `json:"shout,omitempty"`
+}
+
+// DecodeGameRequest reads and validates a GameRequest from its JSON body. The
+// ruleset of the game is copied to the board, where strategies look for it.
+func DecodeGameRequest(data []byte) (GameRequest, error) {
+	request := GameRequest{}
+	err := json.Unmarshal(data, &request)
+	if err != nil {
+		return request, fmt.Errorf("invalid game request: %w", err)
+	}
+	request.Board.Ruleset = request.Game.Ruleset
+	return request, request.validate()
+}
+
+func (request GameRequest) validate() error {
+	if len(request.Game.ID) == 0 {
+		return errors.New("invalid game request: missing game id")
+	}
+	if len(request.You.ID) == 0 {
+		return errors.New("invalid game request: missing snake id")
+	}
+	if request.Board.Width <= 0 || request.Board.Height <= 0 {
+		return fmt.Errorf("invalid game request: board size %dx%d", request.Board.Width, request.Board.Height)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+)
+
+func TestDecodeGameRequest(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Body  string
+		Valid bool
+	}{
+		{
+			Name:  "Expect complete request to be valid",
+			Body:  `{"game": {"id": "g", "ruleset": {"name": "wrapped"}}, "board": {"width": 11, "height": 11}, "you": {"id": "s"}}`,
+			Valid: true,
+		},
+		{Name: "Expect invalid JSON to be rejected", Body: `{"game": `},
+		{Name: "Expect missing game id to be rejected", Body: `{"board": {"width": 11, "height": 11}, "you": {"id": "s"}}`},
+		{Name: "Expect missing snake id to be rejected", Body: `{"game": {"id": "g"}, "board": {"width": 11, "height": 11}}`},
+		{Name: "Expect empty board to be rejected", Body: `{"game": {"id": "g"}, "you": {"id": "s"}}`},
+	}
+
+	for _, tt := range tests {
+		request, err := DecodeGameRequest([]byte(tt.Body))
+
+		if (err == nil) != tt.Valid {
+			t.Errorf("%s: got %v", tt.Name, err)
+		}
+		if tt.Valid && request.Board.Ruleset.Name != game.RulesetWrapped {
+			t.Errorf("%s: expected ruleset to be copied to the board, got %+v", tt.Name, request.Board.Ruleset)
+		}
+	}
+}
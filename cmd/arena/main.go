@@ -0,0 +1,110 @@
+// Command arena plays games between strategies locally and prints the results.
+//
+// Usage:
+//
+//	go run ./cmd/arena -games 10 CircleInnerBorder NearestFoodStrategy FoodOnlyWhenHealthLow
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+func main() {
+	width := flag.Int("width", 11, "width of the board")
+	height := flag.Int("height", 11, "height of the board")
+	games := flag.Int("games", 1, "number of games to play")
+	seed := flag.Int64("seed", 1, "seed of the first game, following games use the next seeds")
+	maxTurns := flag.Int("max-turns", 1000, "number of turns after which a game ends in a draw")
+	foodSpawnChance := flag.Int("food-spawn-chance", 15, "chance in percent to spawn food each turn")
+	minimumFood := flag.Int("minimum-food", 1, "amount of food that is always on the board")
+	ruleset := flag.String("ruleset", game.RulesetStandard, "name of the ruleset, \""+game.RulesetWrapped+"\" for boards without walls")
+	moveTimeout := flag.Duration("move-timeout", 0, "time each snake has to decide on a move, unlimited if zero")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] strategy...\n\nStrategies: %s\n\nFlags:\n", os.Args[0], strings.Join(game.StrategyNames(), ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var players []game.Player
+	for _, name := range flag.Args() {
+		strategy, err := game.NewStrategy(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		players = append(players, game.Player{Name: name, Strategy: strategy})
+	}
+
+	// Strategies warn about moves without a way out, which are common in
+	// local games and would drown the results.
+	quiet := logging.New(os.Stderr, logging.LevelError, logging.FormatText)
+	wins := map[string]int{}
+	draws := 0
+	for i := 0; i < *games; i++ {
+		match := game.Match{
+			Width:       *width,
+			Height:      *height,
+			Players:     players,
+			Seed:        *seed + int64(i),
+			MaxTurns:    *maxTurns,
+			MoveTimeout: *moveTimeout,
+			Ruleset:     game.Ruleset{Name: *ruleset},
+			Logger:      quiet,
+			Rules: game.Rules{
+				FoodSpawnChance: *foodSpawnChance,
+				MinimumFood:     *minimumFood,
+			},
+		}
+		result, err := match.Play()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		printResult(match, result)
+
+		if result.Winner == "" {
+			draws++
+		} else {
+			wins[result.Winner]++
+		}
+	}
+
+	if *games > 1 {
+		printSummary(players, wins, draws, *games)
+	}
+}
+
+func printResult(match game.Match, result game.MatchResult) {
+	winner := "draw"
+	if result.Winner != "" {
+		winner = fmt.Sprintf("%s (%s) wins", result.Winner, result.Names[result.Winner])
+	}
+	fmt.Printf("Game with seed %d: %s after %d turns\n", match.Seed, winner, result.Turns)
+	for _, elimination := range result.Eliminations {
+		cause := elimination.Cause
+		if elimination.By != "" && elimination.By != elimination.SnakeID {
+			cause = fmt.Sprintf("%s with %s", cause, elimination.By)
+		}
+		fmt.Printf("  %s (%s): %s on turn %d\n", elimination.SnakeID, result.Names[elimination.SnakeID], cause, elimination.Turn)
+	}
+}
+
+func printSummary(players []game.Player, wins map[string]int, draws int, games int) {
+	fmt.Printf("\nResults after %d games:\n", games)
+	for i, player := range players {
+		id := fmt.Sprintf("snake-%d", i+1)
+		fmt.Printf("  %-8s %-24s %d wins\n", id, player.Name, wins[id])
+	}
+	fmt.Printf("  %-33s %d\n", "draws", draws)
+}
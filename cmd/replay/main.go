@@ -0,0 +1,243 @@
+// Command replay feeds recorded games to a strategy and reports the turns in
+// which it now decides differently than in the recording.
+//
+// It reads recordings written by the server when RECORD_DIR is set (*.jsonl)
+// as well as single GameRequest captures (*.json), e.g. exported from the
+// engine. For captures, the move that was played is derived from the head of
+// our snake in the next turn.
+//
+// Usage:
+//
+//	go run ./cmd/replay -strategy CircleInnerBorder recordings/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flutter-clutter/starter-snake-go/api"
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/recording"
+)
+
+// turn is a recorded request together with the move we played.
+type turn struct {
+	request api.GameRequest
+	// played is empty if it is not known.
+	played game.SnakeDirectionType
+	source string
+}
+
+func main() {
+	strategyName := flag.String("strategy", "CircleInnerBorder", "strategy to replay the games with")
+	weightsFile := flag.String("weights", "", "file with evaluation weights for the strategy")
+	timeout := flag.Duration("timeout", 0, "time the strategy has for each move, unlimited if zero")
+	verbose := flag.Bool("v", false, "also print turns in which the move did not change")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] file-or-directory...\n\nStrategies: %s\n\nFlags:\n", os.Args[0], strings.Join(game.StrategyNames(), ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var evaluator game.Evaluator
+	if *weightsFile != "" {
+		weights, err := game.LoadWeights(*weightsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		evaluator, _ = weights.Evaluator()
+	}
+	if _, err := game.NewStrategy(*strategyName); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	turns, err := loadTurns(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	games := groupByGame(turns)
+
+	replayed, changed := 0, 0
+	for _, key := range sortedKeys(games) {
+		strategy, _ := game.NewEvaluatedStrategy(*strategyName, evaluator)
+		snake := &game.StrategicBattlesnake{Action: game.ApproachBorder{}, Strategy: strategy}
+		for _, t := range games[key] {
+			snake.Snake = t.request.You
+			move, err := nextMove(snake, t.request.Board, *timeout)
+			description := game.ActionName(snake.Action)
+			if err != nil {
+				description = fmt.Sprintf("fallback: %v", err)
+			}
+
+			if t.played == "" {
+				if *verbose {
+					fmt.Printf("%s turn %d: unknown, now %s (%s)\n", t.request.Game.ID, t.request.Turn, move, description)
+				}
+				continue
+			}
+			replayed++
+			if move != t.played {
+				changed++
+				fmt.Printf("%s turn %d: played %s, now %s (%s) in %s\n", t.request.Game.ID, t.request.Turn, t.played, move, description, t.source)
+			} else if *verbose {
+				fmt.Printf("%s turn %d: %s (%s)\n", t.request.Game.ID, t.request.Turn, move, description)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d of %d turns in %d games changed\n", changed, replayed, len(games))
+	if changed > 0 {
+		os.Exit(1)
+	}
+}
+
+func nextMove(snake *game.StrategicBattlesnake, board game.Board, timeout time.Duration) (game.SnakeDirectionType, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return snake.NextMove(ctx, board)
+}
+
+// loadTurns reads all recordings and captures in the given files and
+// directories.
+func loadTurns(paths []string) ([]turn, error) {
+	var turns []turn
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			var loaded []turn
+			switch filepath.Ext(path) {
+			case ".jsonl":
+				loaded, err = loadRecording(path)
+			case ".json":
+				loaded, err = loadCapture(path)
+			default:
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("could not read %s: %v", path, err)
+			}
+			turns = append(turns, loaded...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return turns, nil
+}
+
+func loadRecording(path string) ([]turn, error) {
+	entries, err := recording.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var turns []turn
+	for _, entry := range entries {
+		if entry.Type != recording.TypeMove {
+			continue
+		}
+		request, err := api.DecodeGameRequest(entry.Request)
+		if err != nil {
+			return nil, err
+		}
+		var response api.MoveResponse
+		if err := json.Unmarshal(entry.Response, &response); err != nil {
+			return nil, err
+		}
+		turns = append(turns, turn{request: request, played: response.Move, source: path})
+	}
+	return turns, nil
+}
+
+func loadCapture(path string) ([]turn, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	request, err := api.DecodeGameRequest(data)
+	if err != nil {
+		return nil, err
+	}
+	return []turn{{request: request, source: path}}, nil
+}
+
+// groupByGame sorts the turns of each of our snakes in each game. Moves that
+// are not known are derived from the next turn.
+func groupByGame(turns []turn) map[string][]turn {
+	games := map[string][]turn{}
+	for _, t := range turns {
+		key := t.request.Game.ID + "/" + t.request.You.ID
+		games[key] = append(games[key], t)
+	}
+	for _, gameTurns := range games {
+		sort.SliceStable(gameTurns, func(i, j int) bool {
+			return gameTurns[i].request.Turn < gameTurns[j].request.Turn
+		})
+		for i := range gameTurns[:len(gameTurns)-1] {
+			current, next := gameTurns[i].request, gameTurns[i+1].request
+			if gameTurns[i].played == "" && next.Turn == current.Turn+1 {
+				gameTurns[i].played = direction(current.You.Head, next.You.Head, current.Board)
+			}
+		}
+	}
+	return games
+}
+
+// direction returns the move that leads from one cell to its neighbor, which
+// may be on the opposite edge of wrapped boards.
+func direction(from game.Coord, to game.Coord, board game.Board) game.SnakeDirectionType {
+	dx := to.X - from.X
+	dy := to.Y - from.Y
+	if board.Ruleset.Name == game.RulesetWrapped {
+		// On boards only 2 cells wide or high, both moves lead to the
+		// other cell; the step is taken as it is then.
+		if board.Width > 2 && (dx == board.Width-1 || dx == -(board.Width-1)) {
+			dx = -dx / (board.Width - 1)
+		}
+		if board.Height > 2 && (dy == board.Height-1 || dy == -(board.Height-1)) {
+			dy = -dy / (board.Height - 1)
+		}
+	}
+	switch {
+	case dx == 1 && dy == 0:
+		return game.SnakeDirection.RIGHT
+	case dx == -1 && dy == 0:
+		return game.SnakeDirection.LEFT
+	case dx == 0 && dy == 1:
+		return game.SnakeDirection.UP
+	case dx == 0 && dy == -1:
+		return game.SnakeDirection.DOWN
+	}
+	return ""
+}
+
+func sortedKeys(games map[string][]turn) []string {
+	var keys []string
+	for key := range games {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flutter-clutter/starter-snake-go/api"
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/recording"
+)
+
+// gameRequest returns the body of a request of the given turn with our head
+// at the coord.
+func gameRequest(gameID string, turn int, head game.Coord) []byte {
+	return []byte(fmt.Sprintf(`{"game": {"id": %q}, "turn": %d, "board": {"width": 11, "height": 11}, "you": {"id": "me", "head": {"x": %d, "y": %d}}}`,
+		gameID, turn, head.X, head.Y))
+}
+
+func createTurn(t *testing.T, gameID string, number int, head game.Coord, played game.SnakeDirectionType) turn {
+	request, err := api.DecodeGameRequest(gameRequest(gameID, number, head))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return turn{request: request, played: played}
+}
+
+func TestDirection(t *testing.T) {
+	bounded := game.Board{Width: 11, Height: 11}
+	wrapped := game.Board{Width: 11, Height: 11, Ruleset: game.Ruleset{Name: game.RulesetWrapped}}
+	narrow := game.Board{Width: 2, Height: 11}
+	narrowWrapped := game.Board{Width: 2, Height: 11, Ruleset: game.Ruleset{Name: game.RulesetWrapped}}
+
+	tests := []struct {
+		Name     string
+		From     game.Coord
+		To       game.Coord
+		Board    game.Board
+		Expected game.SnakeDirectionType
+	}{
+		{"Expect step to the right", game.Coord{X: 4, Y: 4}, game.Coord{X: 5, Y: 4}, bounded, game.SnakeDirection.RIGHT},
+		{"Expect step down", game.Coord{X: 4, Y: 4}, game.Coord{X: 4, Y: 3}, bounded, game.SnakeDirection.DOWN},
+		{"Expect no move for jumps", game.Coord{X: 4, Y: 4}, game.Coord{X: 6, Y: 4}, bounded, ""},
+		{"Expect no move across the edge of bounded boards", game.Coord{X: 0, Y: 4}, game.Coord{X: 10, Y: 4}, bounded, ""},
+		{"Expect step left across the edge of wrapped boards", game.Coord{X: 0, Y: 4}, game.Coord{X: 10, Y: 4}, wrapped, game.SnakeDirection.LEFT},
+		{"Expect step up across the edge of wrapped boards", game.Coord{X: 4, Y: 10}, game.Coord{X: 4, Y: 0}, wrapped, game.SnakeDirection.UP},
+		{"Expect step to the right on narrow boards", game.Coord{X: 0, Y: 4}, game.Coord{X: 1, Y: 4}, narrow, game.SnakeDirection.RIGHT},
+		{"Expect step to the right on narrow wrapped boards", game.Coord{X: 0, Y: 4}, game.Coord{X: 1, Y: 4}, narrowWrapped, game.SnakeDirection.RIGHT},
+	}
+
+	for _, tt := range tests {
+		if move := direction(tt.From, tt.To, tt.Board); move != tt.Expected {
+			t.Errorf("%s: expected %q, got %q", tt.Name, tt.Expected, move)
+		}
+	}
+}
+
+func TestGroupByGame(t *testing.T) {
+	turns := []turn{
+		createTurn(t, "game-1", 2, game.Coord{X: 4, Y: 6}, ""),
+		createTurn(t, "game-1", 0, game.Coord{X: 5, Y: 5}, game.SnakeDirection.LEFT),
+		createTurn(t, "game-2", 0, game.Coord{X: 1, Y: 1}, ""),
+		createTurn(t, "game-1", 1, game.Coord{X: 4, Y: 5}, ""),
+		createTurn(t, "game-2", 2, game.Coord{X: 1, Y: 3}, ""),
+	}
+
+	games := groupByGame(turns)
+
+	tests := []struct {
+		Key      string
+		Expected []game.SnakeDirectionType
+	}{
+		// The recorded move is kept, missing ones are derived from the
+		// next turn. The last move of a game is unknown.
+		{"game-1/me", []game.SnakeDirectionType{game.SnakeDirection.LEFT, game.SnakeDirection.UP, ""}},
+		// Moves are not derived across missing turns.
+		{"game-2/me", []game.SnakeDirectionType{"", ""}},
+	}
+	if len(games) != len(tests) {
+		t.Fatalf("Expected %d games, got %v", len(tests), games)
+	}
+	for _, tt := range tests {
+		gameTurns := games[tt.Key]
+		if len(gameTurns) != len(tt.Expected) {
+			t.Fatalf("Expected %d turns of %s, got %v", len(tt.Expected), tt.Key, gameTurns)
+		}
+		for i, expected := range tt.Expected {
+			if i > 0 && gameTurns[i].request.Turn <= gameTurns[i-1].request.Turn {
+				t.Errorf("Expected turns of %s to be sorted, got %v", tt.Key, gameTurns)
+			}
+			if gameTurns[i].played != expected {
+				t.Errorf("Expected move %q on turn %d of %s, got %q", expected, gameTurns[i].request.Turn, tt.Key, gameTurns[i].played)
+			}
+		}
+	}
+}
+
+func TestLoadTurns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	recorder, err := recording.NewRecorder(filepath.Join(dir, "recordings"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, _ := json.Marshal(api.MoveResponse{Move: game.SnakeDirection.UP})
+	recorder.Record("game-1", recording.Entry{Type: recording.TypeStart, Request: gameRequest("game-1", 0, game.Coord{})})
+	recorder.Record("game-1", recording.Entry{Type: recording.TypeMove, Request: gameRequest("game-1", 0, game.Coord{}), Response: response})
+	recorder.Record("game-1", recording.Entry{Type: recording.TypeEnd, Request: gameRequest("game-1", 1, game.Coord{Y: 1})})
+	ioutil.WriteFile(filepath.Join(dir, "capture.json"), gameRequest("game-2", 3, game.Coord{}), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a game"), 0644)
+
+	turns, err := loadTurns([]string{dir})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]turn{}
+	for _, loaded := range turns {
+		sources[filepath.Base(loaded.source)] = loaded
+	}
+	tests := []struct {
+		Source   string
+		GameID   string
+		Turn     int
+		Expected game.SnakeDirectionType
+	}{
+		{"game-1.jsonl", "game-1", 0, game.SnakeDirection.UP},
+		{"capture.json", "game-2", 3, ""},
+	}
+	if len(turns) != len(tests) {
+		t.Fatalf("Expected only the move of the recording and the capture, got %v", turns)
+	}
+	for _, tt := range tests {
+		loaded, ok := sources[tt.Source]
+		if !ok || loaded.request.Game.ID != tt.GameID || loaded.request.Turn != tt.Turn || loaded.played != tt.Expected {
+			t.Errorf("Expected turn %d of %s with move %q from %s, got %+v", tt.Turn, tt.GameID, tt.Expected, tt.Source, loaded)
+		}
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"game": `), 0644)
+	if _, err := loadTurns([]string{dir}); err == nil {
+		t.Errorf("Expected an error for an invalid capture")
+	}
+}
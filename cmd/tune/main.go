@@ -0,0 +1,357 @@
+// Command tune optimizes the weights of the evaluation terms by self-play with
+// a genetic algorithm and writes the best weights to a file that the server
+// loads from WEIGHTS_FILE.
+//
+// Every generation, each set of weights plays duels against other sets of the
+// population on headless boards. The next generation keeps the best sets and
+// breeds new ones from parents chosen by tournament selection, with uniform
+// crossover and Gaussian mutation.
+//
+// Usage:
+//
+//	go run ./cmd/tune -generations 20 -out weights.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+type config struct {
+	strategy    string
+	terms       []string
+	population  int
+	generations int
+	games       int
+	tournament  int
+	elite       int
+	mutation    float64
+	workers     int
+	width       int
+	height      int
+	maxTurns    int
+	rules       game.Rules
+}
+
+// individual is a set of weights together with the results of its games.
+type individual struct {
+	weights game.Weights
+	points  float64
+	played  int
+}
+
+func (ind *individual) fitness() float64 {
+	if ind.played == 0 {
+		return 0
+	}
+	return ind.points / float64(ind.played)
+}
+
+// pairing is a game to be played between two individuals.
+type pairing struct {
+	first  int
+	second int
+	seed   int64
+}
+
+type pairingResult struct {
+	pairing
+	winner string
+}
+
+func main() {
+	var cfg config
+	var terms, start, out string
+	var seed int64
+	flag.StringVar(&cfg.strategy, "strategy", "Greedy", "strategy that plays with the weights")
+	flag.StringVar(&terms, "terms", strings.Join(game.TermNames(), ","), "comma separated terms to tune")
+	flag.StringVar(&start, "start", "", "file with the weights to start from, the default weights if empty")
+	flag.StringVar(&out, "out", "weights.json", "file to write the best weights to after each generation")
+	flag.IntVar(&cfg.population, "population", 12, "number of weight sets per generation")
+	flag.IntVar(&cfg.generations, "generations", 10, "number of generations")
+	flag.IntVar(&cfg.games, "games", 6, "number of games each weight set starts per generation")
+	flag.IntVar(&cfg.tournament, "tournament", 3, "number of weight sets competing to become a parent")
+	flag.IntVar(&cfg.elite, "elite", 2, "number of best weight sets kept unchanged")
+	flag.Float64Var(&cfg.mutation, "mutation", 0.3, "standard deviation of mutations, relative to the weight")
+	flag.IntVar(&cfg.workers, "workers", runtime.NumCPU(), "number of games played in parallel")
+	flag.Int64Var(&seed, "seed", 1, "seed for the algorithm and the games")
+	flag.IntVar(&cfg.width, "width", 11, "width of the board")
+	flag.IntVar(&cfg.height, "height", 11, "height of the board")
+	flag.IntVar(&cfg.maxTurns, "max-turns", 300, "number of turns after which a game ends in a draw")
+	flag.IntVar(&cfg.rules.FoodSpawnChance, "food-spawn-chance", 15, "chance in percent to spawn food each turn")
+	flag.IntVar(&cfg.rules.MinimumFood, "minimum-food", 1, "amount of food that is always on the board")
+	flag.Parse()
+
+	cfg.terms = strings.Split(terms, ",")
+	if err := validate(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	initial := game.DefaultWeights
+	if start != "" {
+		weights, err := game.LoadWeights(start)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		initial = weights
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	population := initialPopulation(cfg, initial, rnd)
+	for generation := 1; generation <= cfg.generations; generation++ {
+		play(cfg, population, rnd)
+		sort.SliceStable(population, func(i, j int) bool {
+			return population[i].fitness() > population[j].fitness()
+		})
+
+		best := population[0]
+		fmt.Printf("Generation %d: best scored %.2f with %s\n", generation, best.fitness(), formatWeights(best.weights))
+		if err := writeWeights(out, best.weights); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if generation < cfg.generations {
+			population = nextGeneration(cfg, population, rnd)
+		}
+	}
+	fmt.Printf("Wrote best weights to %s\n", out)
+}
+
+func validate(cfg config) error {
+	if _, err := game.NewStrategy(cfg.strategy); err != nil {
+		return err
+	}
+	if !game.IsEvaluatedStrategy(cfg.strategy) {
+		var evaluated []string
+		for _, name := range game.StrategyNames() {
+			if game.IsEvaluatedStrategy(name) {
+				evaluated = append(evaluated, name)
+			}
+		}
+		return fmt.Errorf("strategy %q does not use evaluation weights, use one of %s", cfg.strategy, strings.Join(evaluated, ", "))
+	}
+	weights := game.Weights{}
+	for _, term := range cfg.terms {
+		weights[term] = 0
+	}
+	if _, err := weights.Evaluator(); err != nil {
+		return err
+	}
+	if cfg.width < game.MinimumBoardSize || cfg.height < game.MinimumBoardSize {
+		return fmt.Errorf("width and height must be at least %d", game.MinimumBoardSize)
+	}
+	if cfg.population < 2 {
+		return fmt.Errorf("population must be at least 2")
+	}
+	if cfg.elite < 0 || cfg.elite >= cfg.population {
+		return fmt.Errorf("elite must be smaller than the population")
+	}
+	if cfg.tournament < 1 || cfg.workers < 1 {
+		return fmt.Errorf("tournament and workers must be at least 1")
+	}
+	return nil
+}
+
+// initialPopulation returns the initial weights and mutations of them. Terms to
+// tune that are missing from the initial weights start at zero.
+func initialPopulation(cfg config, initial game.Weights, rnd *rand.Rand) []*individual {
+	weights := game.Weights{}
+	for term, weight := range initial {
+		weights[term] = weight
+	}
+	for _, term := range cfg.terms {
+		if _, ok := weights[term]; !ok {
+			weights[term] = 0
+		}
+	}
+
+	population := []*individual{{weights: weights}}
+	for len(population) < cfg.population {
+		population = append(population, &individual{weights: mutate(cfg, weights, rnd)})
+	}
+	return population
+}
+
+// play lets every individual play its games against random other individuals
+// of the population. Both players of a game get its result.
+func play(cfg config, population []*individual, rnd *rand.Rand) {
+	var pairings []pairing
+	for i := range population {
+		for g := 0; g < cfg.games; g++ {
+			opponent := rnd.Intn(len(population) - 1)
+			if opponent >= i {
+				opponent++
+			}
+			// Alternate the starting positions.
+			if g%2 == 0 {
+				pairings = append(pairings, pairing{i, opponent, rnd.Int63()})
+			} else {
+				pairings = append(pairings, pairing{opponent, i, rnd.Int63()})
+			}
+		}
+	}
+
+	for _, ind := range population {
+		ind.points = 0
+		ind.played = 0
+	}
+
+	jobs := make(chan pairing)
+	results := make(chan pairingResult)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- pairingResult{job, playGame(cfg, population[job.first].weights, population[job.second].weights, job.seed)}
+			}
+		}()
+	}
+	go func() {
+		for _, job := range pairings {
+			jobs <- job
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		first := population[result.first]
+		second := population[result.second]
+		first.played++
+		second.played++
+		switch result.winner {
+		case "snake-1":
+			first.points++
+		case "snake-2":
+			second.points++
+		default:
+			first.points += 0.5
+			second.points += 0.5
+		}
+	}
+}
+
+// quiet keeps the warnings of the strategies out of the progress of a run.
+var quiet = logging.New(os.Stderr, logging.LevelError, logging.FormatText)
+
+// playGame plays a duel between two sets of weights and returns the ID of the
+// winning snake, empty for a draw.
+func playGame(cfg config, first game.Weights, second game.Weights, seed int64) string {
+	var players []game.Player
+	for i, weights := range []game.Weights{first, second} {
+		evaluator, _ := weights.Evaluator()
+		strategy, _ := game.NewEvaluatedStrategy(cfg.strategy, evaluator)
+		players = append(players, game.Player{Name: fmt.Sprintf("weights-%d", i+1), Strategy: strategy})
+	}
+	match := game.Match{
+		Width:    cfg.width,
+		Height:   cfg.height,
+		Players:  players,
+		Seed:     seed,
+		MaxTurns: cfg.maxTurns,
+		Rules:    cfg.rules,
+		Logger:   quiet,
+	}
+	// A duel always fits on the board.
+	result, _ := match.Play()
+	return result.Winner
+}
+
+// nextGeneration keeps the elite and breeds the rest of the new population.
+// The population has to be sorted by fitness.
+func nextGeneration(cfg config, population []*individual, rnd *rand.Rand) []*individual {
+	var next []*individual
+	for _, ind := range population[:cfg.elite] {
+		next = append(next, &individual{weights: ind.weights})
+	}
+	for len(next) < cfg.population {
+		mother := selectParent(cfg, population, rnd)
+		father := selectParent(cfg, population, rnd)
+		next = append(next, &individual{weights: mutate(cfg, crossover(mother.weights, father.weights, rnd), rnd)})
+	}
+	return next
+}
+
+// selectParent returns the fittest of randomly chosen individuals.
+func selectParent(cfg config, population []*individual, rnd *rand.Rand) *individual {
+	var best *individual
+	for i := 0; i < cfg.tournament; i++ {
+		candidate := population[rnd.Intn(len(population))]
+		if best == nil || candidate.fitness() > best.fitness() {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// crossover takes each weight from one of the parents at random.
+func crossover(mother game.Weights, father game.Weights, rnd *rand.Rand) game.Weights {
+	child := game.Weights{}
+	for term, weight := range mother {
+		child[term] = weight
+	}
+	for _, term := range sortedTerms(father) {
+		if _, ok := child[term]; !ok || rnd.Intn(2) == 0 {
+			child[term] = father[term]
+		}
+	}
+	return child
+}
+
+// mutate returns a copy of the weights in which every tuned weight changed by
+// a normally distributed amount. Weights near zero change by at least a
+// tenth of the mutation.
+func mutate(cfg config, weights game.Weights, rnd *rand.Rand) game.Weights {
+	mutated := game.Weights{}
+	for term, weight := range weights {
+		mutated[term] = weight
+	}
+	for _, term := range cfg.terms {
+		scale := math.Max(math.Abs(mutated[term]), 0.1)
+		mutated[term] += rnd.NormFloat64() * cfg.mutation * scale
+	}
+	return mutated
+}
+
+func sortedTerms(weights game.Weights) []string {
+	var terms []string
+	for term := range weights {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func formatWeights(weights game.Weights) string {
+	var parts []string
+	for _, term := range sortedTerms(weights) {
+		parts = append(parts, fmt.Sprintf("%s=%.3g", term, weights[term]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func writeWeights(path string, weights game.Weights) error {
+	data, err := json.MarshalIndent(weights, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,332 @@
+// Command viewer writes a recorded game (see RECORD_DIR) into a single HTML
+// file that replays it in the browser, without any network access. The page
+// animates the board, shows the strategy and action that chose each of our
+// moves and graphs the health of all snakes.
+//
+// Usage:
+//
+//	go run ./cmd/viewer -out game.html recordings/game-id.jsonl
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flutter-clutter/starter-snake-go/api"
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/recording"
+)
+
+// replay is the data embedded into the page.
+type replay struct {
+	GameID string  `json:"gameID"`
+	You    string  `json:"you"`
+	Frames []frame `json:"frames"`
+}
+
+// frame is a turn of the game together with our move, if we made one.
+type frame struct {
+	Turn          int                     `json:"turn"`
+	Board         game.Board              `json:"board"`
+	Move          game.SnakeDirectionType `json:"move,omitempty"`
+	Strategy      string                  `json:"strategy,omitempty"`
+	Action        string                  `json:"action,omitempty"`
+	Error         string                  `json:"error,omitempty"`
+	ComputeMillis float64                 `json:"computeMillis"`
+}
+
+func main() {
+	out := flag.String("out", "", "HTML file to write, the recording's name with .html if empty")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] recording.jsonl\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+	if *out == "" {
+		*out = strings.TrimSuffix(path, filepath.Ext(path)) + ".html"
+	}
+
+	data, err := load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	if err := write(*out, data); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d turns of game %s to %s\n", len(data.Frames), data.GameID, *out)
+}
+
+// load turns the moves and the end of a recording into frames. The end has no
+// move and is shown as the end of the game.
+func load(path string) (replay, error) {
+	entries, err := recording.ReadFile(path)
+	if err != nil {
+		return replay{}, err
+	}
+
+	var data replay
+	for _, entry := range entries {
+		if entry.Type == recording.TypeStart {
+			continue
+		}
+		request, err := api.DecodeGameRequest(entry.Request)
+		if err != nil {
+			return replay{}, err
+		}
+		data.GameID = request.Game.ID
+		data.You = request.You.ID
+
+		current := frame{
+			Turn:          request.Turn,
+			Board:         request.Board,
+			Strategy:      entry.Strategy,
+			Action:        entry.Action,
+			Error:         entry.Error,
+			ComputeMillis: entry.ComputeMillis,
+		}
+		if len(entry.Response) > 0 {
+			var response api.MoveResponse
+			if err := json.Unmarshal(entry.Response, &response); err != nil {
+				return replay{}, err
+			}
+			current.Move = response.Move
+		}
+		data.Frames = append(data.Frames, current)
+	}
+	if len(data.Frames) == 0 {
+		return replay{}, fmt.Errorf("recording contains no turns")
+	}
+	return data, nil
+}
+
+func write(path string, data replay) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := page.Execute(file, data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Game {{.GameID}}</title>
+<style>
+  body { font-family: sans-serif; background: #1e1e24; color: #ddd; margin: 2em; }
+  main { display: flex; gap: 2em; align-items: flex-start; }
+  canvas { background: #2b2b33; }
+  button { font-size: 1em; min-width: 3em; }
+  #turn { width: 100%; }
+  #annotation { min-height: 4.5em; line-height: 1.5em; }
+  .error { color: #ff6b6b; }
+  .legend span { display: inline-block; margin-right: 1em; }
+  .swatch { display: inline-block; width: 0.8em; height: 0.8em; margin-right: 0.3em; }
+</style>
+</head>
+<body>
+<h1>Game {{.GameID}}</h1>
+<main>
+  <canvas id="board"></canvas>
+  <div>
+    <p>
+      <button id="first" title="First turn (Home)">⏮</button>
+      <button id="previous" title="Previous turn (←)">◀</button>
+      <button id="play" title="Play or pause (Space)">▶</button>
+      <button id="next" title="Next turn (→)">▶▏</button>
+      <button id="last" title="Last turn (End)">⏭</button>
+    </p>
+    <p><input id="turn" type="range" min="0" value="0"></p>
+    <div id="annotation"></div>
+    <h2>Health</h2>
+    <svg id="health" width="480" height="160"></svg>
+    <div id="legend" class="legend"></div>
+  </div>
+</main>
+<script>
+const game = {{.}};
+const palette = ["#4aa8ff", "#ffad33", "#c76bff", "#33e0a1", "#fff04d", "#4de3ff"];
+const cellSize = 28;
+
+// Every snake keeps its color in all frames, in the order it first appears.
+const colors = {};
+const names = {};
+for (const frame of game.frames) {
+  for (const snake of frame.board.snakes) {
+    if (!(snake.id in colors)) {
+      const configured = snake.customizations && snake.customizations.color;
+      colors[snake.id] = configured || palette[Object.keys(colors).length % palette.length];
+      names[snake.id] = snake.name || snake.id;
+    }
+  }
+}
+
+const canvas = document.getElementById("board");
+const context = canvas.getContext("2d");
+const slider = document.getElementById("turn");
+slider.max = game.frames.length - 1;
+let current = 0;
+let timer = null;
+
+function cellX(coord) { return coord.x * cellSize; }
+function cellY(coord, board) { return (board.height - 1 - coord.y) * cellSize; }
+
+function drawBoard(board) {
+  canvas.width = board.width * cellSize;
+  canvas.height = board.height * cellSize;
+  context.fillStyle = "#2b2b33";
+  context.fillRect(0, 0, canvas.width, canvas.height);
+  context.strokeStyle = "#3a3a44";
+  for (let x = 0; x < board.width; x++) {
+    for (let y = 0; y < board.height; y++) {
+      context.strokeRect(x * cellSize, y * cellSize, cellSize, cellSize);
+    }
+  }
+  context.fillStyle = "rgba(160, 160, 170, 0.35)";
+  for (const hazard of board.hazards || []) {
+    context.fillRect(cellX(hazard), cellY(hazard, board), cellSize, cellSize);
+  }
+  context.fillStyle = "#ff5c5c";
+  for (const food of board.food || []) {
+    context.beginPath();
+    context.arc(cellX(food) + cellSize / 2, cellY(food, board) + cellSize / 2, cellSize / 4, 0, 2 * Math.PI);
+    context.fill();
+  }
+  for (const snake of board.snakes) {
+    context.fillStyle = colors[snake.id];
+    snake.body.forEach(function (part, i) {
+      const inset = i === 0 ? 1 : 4;
+      context.globalAlpha = i === 0 ? 1 : 0.8;
+      context.fillRect(cellX(part) + inset, cellY(part, board) + inset, cellSize - 2 * inset, cellSize - 2 * inset);
+    });
+    context.globalAlpha = 1;
+    if (snake.id === game.you) {
+      context.strokeStyle = "#fff";
+      context.lineWidth = 2;
+      context.strokeRect(cellX(snake.head) + 1, cellY(snake.head, board) + 1, cellSize - 2, cellSize - 2);
+      context.lineWidth = 1;
+    }
+  }
+}
+
+function annotate(frame) {
+  const annotation = document.getElementById("annotation");
+  annotation.textContent = "";
+  const lines = ["Turn " + frame.turn + " of " + game.frames[game.frames.length - 1].turn];
+  if (frame.move) {
+    lines.push("Moved " + frame.move + " in " + frame.computeMillis.toFixed(1) + " ms");
+    lines.push("Strategy " + (frame.strategy || "unknown") + (frame.action ? ", action " + frame.action : ""));
+  } else {
+    lines.push("Game over");
+  }
+  for (const line of lines) {
+    const div = document.createElement("div");
+    div.textContent = line;
+    annotation.appendChild(div);
+  }
+  if (frame.error) {
+    const div = document.createElement("div");
+    div.className = "error";
+    div.textContent = "Fallback: " + frame.error;
+    annotation.appendChild(div);
+  }
+}
+
+function drawHealth() {
+  const svg = document.getElementById("health");
+  const width = svg.getAttribute("width");
+  const height = svg.getAttribute("height");
+  const step = width / Math.max(game.frames.length - 1, 1);
+  let markup = "";
+  for (const id in colors) {
+    const points = [];
+    game.frames.forEach(function (frame, i) {
+      const snake = frame.board.snakes.find(function (s) { return s.id === id; });
+      if (snake) {
+        points.push((i * step).toFixed(1) + "," + (height - snake.health / 100 * height).toFixed(1));
+      }
+    });
+    markup += '<polyline fill="none" stroke-width="2" stroke="' + colors[id] + '" points="' + points.join(" ") + '"/>';
+  }
+  markup += '<line id="cursor" y1="0" y2="' + height + '" stroke="#888"/>';
+  svg.innerHTML = markup;
+
+  const legend = document.getElementById("legend");
+  for (const id in colors) {
+    const entry = document.createElement("span");
+    const swatch = document.createElement("span");
+    swatch.className = "swatch";
+    swatch.style.background = colors[id];
+    entry.appendChild(swatch);
+    entry.appendChild(document.createTextNode(names[id] + (id === game.you ? " (you)" : "")));
+    legend.appendChild(entry);
+  }
+}
+
+function show(index) {
+  current = Math.max(0, Math.min(index, game.frames.length - 1));
+  slider.value = current;
+  const frame = game.frames[current];
+  drawBoard(frame.board);
+  annotate(frame);
+  const x = current * document.getElementById("health").getAttribute("width") / Math.max(game.frames.length - 1, 1);
+  document.getElementById("cursor").setAttribute("x1", x);
+  document.getElementById("cursor").setAttribute("x2", x);
+  if (current === game.frames.length - 1) {
+    pause();
+  }
+}
+
+function play() {
+  if (current === game.frames.length - 1) {
+    show(0);
+  }
+  timer = setInterval(function () { show(current + 1); }, 250);
+  document.getElementById("play").textContent = "⏸";
+}
+
+function pause() {
+  clearInterval(timer);
+  timer = null;
+  document.getElementById("play").textContent = "▶";
+}
+
+document.getElementById("first").onclick = function () { show(0); };
+document.getElementById("previous").onclick = function () { show(current - 1); };
+document.getElementById("next").onclick = function () { show(current + 1); };
+document.getElementById("last").onclick = function () { show(game.frames.length - 1); };
+document.getElementById("play").onclick = function () { timer ? pause() : play(); };
+slider.oninput = function () { show(Number(slider.value)); };
+document.addEventListener("keydown", function (event) {
+  switch (event.key) {
+  case "ArrowLeft": show(current - 1); break;
+  case "ArrowRight": show(current + 1); break;
+  case "Home": show(0); break;
+  case "End": show(game.frames.length - 1); break;
+  case " ": timer ? pause() : play(); event.preventDefault(); break;
+  }
+});
+
+drawHealth();
+show(0);
+</script>
+</body>
+</html>
+`))
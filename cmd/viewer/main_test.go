@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/recording"
+)
+
+// writeRecording writes the entries into a recording in a new directory,
+// which the caller removes.
+func writeRecording(t *testing.T, entries ...recording.Entry) (string, string) {
+	dir, err := ioutil.TempDir("", "viewer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	path := filepath.Join(dir, "game.jsonl")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir, path
+}
+
+func gameRequest(turn int) json.RawMessage {
+	return json.RawMessage(`{"game": {"id": "game-1"}, "turn": ` + strconv.Itoa(turn) + `, "board": {"width": 11, "height": 11}, "you": {"id": "me"}}`)
+}
+
+func TestLoad(t *testing.T) {
+	dir, path := writeRecording(t,
+		recording.Entry{Type: recording.TypeStart, Request: gameRequest(0)},
+		recording.Entry{Type: recording.TypeMove, Request: gameRequest(0), Response: json.RawMessage(`{"move": "up"}`), Strategy: "Minimax", Action: "Greedy", ComputeMillis: 12},
+		recording.Entry{Type: recording.TypeMove, Request: gameRequest(1), Response: json.RawMessage(`{"move": "left"}`), Strategy: "Minimax"},
+		recording.Entry{Type: recording.TypeEnd, Request: gameRequest(2)},
+	)
+	defer os.RemoveAll(dir)
+
+	data, err := load(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.GameID != "game-1" || data.You != "me" {
+		t.Errorf("Expected game-1 played by me, got %s played by %s", data.GameID, data.You)
+	}
+	expected := []frame{
+		{Turn: 0, Move: game.SnakeDirection.UP, Strategy: "Minimax", Action: "Greedy", ComputeMillis: 12},
+		{Turn: 1, Move: game.SnakeDirection.LEFT, Strategy: "Minimax"},
+		{Turn: 2},
+	}
+	if len(data.Frames) != len(expected) {
+		t.Fatalf("Expected the start to be skipped and %d frames, got %+v", len(expected), data.Frames)
+	}
+	for i, want := range expected {
+		got := data.Frames[i]
+		if got.Turn != want.Turn || got.Move != want.Move || got.Strategy != want.Strategy || got.Action != want.Action || got.ComputeMillis != want.ComputeMillis {
+			t.Errorf("Expected frame %d to be %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestLoadOfEmptyRecording(t *testing.T) {
+	dir, path := writeRecording(t, recording.Entry{Type: recording.TypeStart, Request: gameRequest(0)})
+	defer os.RemoveAll(dir)
+
+	if _, err := load(path); err == nil {
+		t.Errorf("Expected an error for a recording without turns")
+	}
+}
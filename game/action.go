@@ -1,23 +1,29 @@
 package game
 
-import "sort"
+import (
+	"context"
+	"reflect"
+
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
 
 var possibleMoves []SnakeDirectionType = []SnakeDirectionType{SnakeDirection.UP, SnakeDirection.RIGHT, SnakeDirection.DOWN, SnakeDirection.LEFT}
 
 type Action interface {
-	Execute(Battlesnake, Board) SnakeDirectionType
+	Execute(context.Context, Battlesnake, Board) SnakeDirectionType
 }
 
 type CollectNearestFood struct{}
 
-func (CollectNearestFood) Execute(snake Battlesnake, board Board) SnakeDirectionType {
+func (CollectNearestFood) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
 	var move SnakeDirectionType
 
 	move = approachNearestFood(snake, board)
 
-	var newCoord Coord = snake.Head.newCoordFromMove(move)
+	var newCoord Coord = snake.Head.neighbor(move, board)
 	if !newCoord.isSafe(snake, board) {
-		move = getSafeMove(snake, board)
+		traceFrom(ctx).reject("CollectNearestFood", move, newCoord.unsafeReason(snake, board))
+		move = getSafeMove(ctx, snake, board)
 	}
 
 	return move
@@ -31,46 +37,72 @@ func approachNearestFood(battlesnake Battlesnake, board Board) SnakeDirectionTyp
 		return SnakeDirection.UP
 	}
 
-	return moveTowardsNearestCoord(battlesnake.Head, board.Food)
+	if path, ok := findPath(battlesnake, board, board.Food); ok {
+		return battlesnake.Head.directionTo(path[0], board)
+	}
 
+	return moveTowardsNearestCoord(battlesnake.Head, board.Food, board)
 }
 
-func getSafeMove(battlesnake Battlesnake, board Board) SnakeDirectionType {
+// getSafeMove returns the first safe move, preferring moves that don't enter
+// hazards. Up is returned if there is no safe move.
+func getSafeMove(ctx context.Context, battlesnake Battlesnake, board Board) SnakeDirectionType {
+	trace := traceFrom(ctx)
+	var hazardousMove SnakeDirectionType
 	for _, v := range possibleMoves {
-		newCoord := battlesnake.Head.newCoordFromMove(v)
-		if newCoord.isSafe(battlesnake, board) {
+		newCoord := battlesnake.Head.neighbor(v, board)
+		if !newCoord.isSafe(battlesnake, board) {
+			trace.reject("SafeMove", v, newCoord.unsafeReason(battlesnake, board))
+			continue
+		}
+		if !newCoord.isHazard(board) {
+			trace.choose("SafeMove", v, "")
 			return v
 		}
+		trace.candidate("SafeMove", v, "hazard")
+		if len(hazardousMove) == 0 {
+			hazardousMove = v
+		}
+	}
+	if len(hazardousMove) > 0 {
+		trace.fallback("SafeMove", hazardousMove, "only hazardous moves are safe")
+		return hazardousMove
 	}
 
-	println("No safe move found")
+	logging.FromContext(ctx).Warn("No safe move found")
+	trace.fallback("SafeMove", SnakeDirection.UP, "No safe move found")
 	return SnakeDirection.UP
 }
 
-func getNextMoveAlongBorder(battlesnake Battlesnake, board Board) SnakeDirectionType {
+func getNextMoveAlongBorder(ctx context.Context, battlesnake Battlesnake, board Board) SnakeDirectionType {
+	if !board.geometry().hasWalls() {
+		traceFrom(ctx).fallback("BorderMove", "", "board has no border")
+		return getSafeMove(ctx, battlesnake, board)
+	}
+
 	if battlesnake.Head.X == 0 {
-		newCoord := battlesnake.Head.newCoordFromMove(SnakeDirection.UP)
+		newCoord := battlesnake.Head.neighbor(SnakeDirection.UP, board)
 		if newCoord.isSafe(battlesnake, board) && newCoord.isAtEdge(battlesnake, board) {
 			return SnakeDirection.UP
 		}
 	}
 
 	if battlesnake.Head.X == board.Width-1 {
-		newCoord := battlesnake.Head.newCoordFromMove(SnakeDirection.DOWN)
+		newCoord := battlesnake.Head.neighbor(SnakeDirection.DOWN, board)
 		if newCoord.isSafe(battlesnake, board) && newCoord.isAtEdge(battlesnake, board) {
 			return SnakeDirection.DOWN
 		}
 	}
 
 	if battlesnake.Head.Y == 0 {
-		newCoord := battlesnake.Head.newCoordFromMove(SnakeDirection.LEFT)
+		newCoord := battlesnake.Head.neighbor(SnakeDirection.LEFT, board)
 		if newCoord.isSafe(battlesnake, board) && newCoord.isAtEdge(battlesnake, board) {
 			return SnakeDirection.LEFT
 		}
 	}
 
 	if battlesnake.Head.Y == board.Height-1 {
-		newCoord := battlesnake.Head.newCoordFromMove(SnakeDirection.RIGHT)
+		newCoord := battlesnake.Head.neighbor(SnakeDirection.RIGHT, board)
 		a := newCoord.isSafe(battlesnake, board)
 		b := newCoord.isAtEdge(battlesnake, board)
 		if a && b {
@@ -78,43 +110,50 @@ func getNextMoveAlongBorder(battlesnake Battlesnake, board Board) SnakeDirection
 		}
 	}
 
-	println("No safe border move found")
-	return getSafeMove(battlesnake, board)
+	logging.FromContext(ctx).Debug("No safe border move found")
+	traceFrom(ctx).fallback("BorderMove", "", "No safe border move found")
+	return getSafeMove(ctx, battlesnake, board)
 }
 
 type MakeSafeMove struct{}
 
-func (MakeSafeMove) Execute(snake Battlesnake, board Board) SnakeDirectionType {
-	return getSafeMove(snake, board)
+func (MakeSafeMove) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	return getSafeMove(ctx, snake, board)
 }
 
 type MakeSafeBorderMove struct{}
 
-func (MakeSafeBorderMove) Execute(snake Battlesnake, board Board) SnakeDirectionType {
-	return getNextMoveAlongBorder(snake, board)
+func (MakeSafeBorderMove) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	return getNextMoveAlongBorder(ctx, snake, board)
 }
 
 type FollowBorder struct{}
 
-func (FollowBorder) Execute(snake Battlesnake, board Board) SnakeDirectionType {
-	return getNextMoveAlongBorder(snake, board)
+func (FollowBorder) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	return getNextMoveAlongBorder(ctx, snake, board)
 }
 
 type ApproachBorder struct{}
 
-func (ApproachBorder) Execute(snake Battlesnake, board Board) SnakeDirectionType {
+func (ApproachBorder) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
 	safeBorderPieces := createListOfSafeBorderPieces(snake, board)
+	if len(safeBorderPieces) == 0 || !board.geometry().hasWalls() {
+		traceFrom(ctx).fallback("ApproachBorder", "", "no safe border to approach")
+		return getSafeMove(ctx, snake, board)
+	}
 
-	byDistance := ByDistance{snake.Head, safeBorderPieces}
-	sort.Sort(ByDistance(byDistance))
-	sortedBorderPieces := byDistance.Coords
-
-	move := moveTowardsNearestCoord(snake.Head, sortedBorderPieces)
+	var move SnakeDirectionType
+	if path, ok := findPath(snake, board, safeBorderPieces); ok {
+		move = snake.Head.directionTo(path[0], board)
+	} else {
+		move = moveTowardsNearestCoord(snake.Head, safeBorderPieces, board)
+	}
 
-	if snake.Head.newCoordFromMove(move).isSafe(snake, board) {
-		return move
+	if newCoord := snake.Head.neighbor(move, board); !newCoord.isSafe(snake, board) {
+		traceFrom(ctx).reject("ApproachBorder", move, newCoord.unsafeReason(snake, board))
+		return getSafeMove(ctx, snake, board)
 	}
-	return getSafeMove(snake, board)
+	return move
 }
 
 func createListOfSafeBorderPieces(snake Battlesnake, board Board) []Coord {
@@ -124,11 +163,11 @@ func createListOfSafeBorderPieces(snake Battlesnake, board Board) []Coord {
 		leftCoord := Coord{0, i}
 		rightCoord := Coord{board.Width - 1, i}
 
-		if leftCoord.isSafe(snake, board) {
+		if leftCoord.isSafe(snake, board) && !leftCoord.isHazard(board) {
 			safeBorderPieces = append(safeBorderPieces, leftCoord)
 		}
 
-		if rightCoord.isSafe(snake, board) {
+		if rightCoord.isSafe(snake, board) && !rightCoord.isHazard(board) {
 			safeBorderPieces = append(safeBorderPieces, rightCoord)
 		}
 	}
@@ -137,11 +176,11 @@ func createListOfSafeBorderPieces(snake Battlesnake, board Board) []Coord {
 		upperCoord := Coord{i, 0}
 		lowerCoord := Coord{i, board.Height - 1}
 
-		if upperCoord.isSafe(snake, board) {
+		if upperCoord.isSafe(snake, board) && !upperCoord.isHazard(board) {
 			safeBorderPieces = append(safeBorderPieces, upperCoord)
 		}
 
-		if lowerCoord.isSafe(snake, board) {
+		if lowerCoord.isSafe(snake, board) && !lowerCoord.isHazard(board) {
 			safeBorderPieces = append(safeBorderPieces, lowerCoord)
 		}
 	}
@@ -149,29 +188,42 @@ func createListOfSafeBorderPieces(snake Battlesnake, board Board) []Coord {
 	return safeBorderPieces
 }
 
-func moveTowardsNearestCoord(snakeCoord Coord, allowedCoords []Coord) SnakeDirectionType {
+func moveTowardsNearestCoord(snakeCoord Coord, allowedCoords []Coord, board Board) SnakeDirectionType {
 	var minDistanceCoord Coord = allowedCoords[0]
 
 	for _, v := range allowedCoords {
-		if snakeCoord.distanceToOther(minDistanceCoord) > snakeCoord.distanceToOther(v) {
+		if snakeCoord.distanceOn(minDistanceCoord, board) > snakeCoord.distanceOn(v, board) {
 			minDistanceCoord = Coord{v.X, v.Y}
 		}
 	}
-	if minDistanceCoord.X > snakeCoord.X {
-		return SnakeDirection.RIGHT
-	}
 
-	if minDistanceCoord.X < snakeCoord.X {
-		return SnakeDirection.LEFT
+	// Horizontal moves are preferred; on wrapped boards the shorter way
+	// may lead across the edge.
+	distance := snakeCoord.distanceOn(minDistanceCoord, board)
+	for _, move := range []SnakeDirectionType{SnakeDirection.RIGHT, SnakeDirection.LEFT, SnakeDirection.UP, SnakeDirection.DOWN} {
+		if snakeCoord.neighbor(move, board).distanceOn(minDistanceCoord, board) < distance {
+			return move
+		}
 	}
 
-	if minDistanceCoord.Y > snakeCoord.Y {
-		return SnakeDirection.UP
-	}
+	return SnakeDirection.UP
+}
 
-	if minDistanceCoord.Y < snakeCoord.Y {
-		return SnakeDirection.DOWN
+// ActionName returns the name of the action's type followed by the actions it
+// wraps, e.g. "AvoidDeadEnds(ContestHeads(FollowBorder))".
+func ActionName(action Action) string {
+	if action == nil {
+		return ""
 	}
-
-	return SnakeDirection.UP
+	value := reflect.Indirect(reflect.ValueOf(action))
+	name := value.Type().Name()
+	if value.Kind() == reflect.Struct {
+		field := value.FieldByName("Action")
+		if field.IsValid() && field.CanInterface() {
+			if wrapped, ok := field.Interface().(Action); ok && wrapped != nil {
+				return name + "(" + ActionName(wrapped) + ")"
+			}
+		}
+	}
+	return name
 }
@@ -1,39 +1,79 @@
 package game
 
 import (
+	"context"
 	"testing"
 )
 
 func TestCollectNearestFoodActionWithFoodInRange(t *testing.T) {
 	tests := []struct {
-		Name        string
-		Food        []Coord
-		SnakeCoords []Coord
-		Expected    SnakeDirectionType
+		Name     string
+		Board    string
+		Expected SnakeDirectionType
 	}{
 		{
-			Name:        "Go left when food is left",
-			Food:        []Coord{{X: 1, Y: 2}},
-			SnakeCoords: []Coord{{X: 2, Y: 2}, {X: 2, Y: 1}},
-			Expected:    SnakeDirection.LEFT,
+			Name: "Go left when food is left",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. * A . . . . . . .
+				. . ^ . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.LEFT,
 		},
 		{
-			Name:        "Go right when food is right",
-			Food:        []Coord{{X: 3, Y: 2}},
-			SnakeCoords: []Coord{{X: 2, Y: 2}, {X: 2, Y: 1}},
-			Expected:    SnakeDirection.RIGHT,
+			Name: "Go right when food is right",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . A * . . . . . .
+				. . ^ . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.RIGHT,
 		},
 		{
-			Name:        "Go up when food is above",
-			Food:        []Coord{{X: 2, Y: 3}},
-			SnakeCoords: []Coord{{X: 2, Y: 2}, {X: 2, Y: 1}},
-			Expected:    SnakeDirection.UP,
+			Name: "Go up when food is above",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . * . . . . . . .
+				. . A . . . . . . .
+				. . ^ . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.UP,
 		},
 		{
-			Name:        "Go down when food is below",
-			Food:        []Coord{{X: 2, Y: 0}},
-			SnakeCoords: []Coord{{X: 2, Y: 1}, {X: 2, Y: 2}},
-			Expected:    SnakeDirection.DOWN,
+			Name: "Go down when food is below",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . v . . . . . . .
+				. . A . . . . . . .
+				. . * . . . . . . .
+			`,
+			Expected: SnakeDirection.DOWN,
 		},
 	}
 
@@ -41,29 +81,12 @@ func TestCollectNearestFoodActionWithFoodInRange(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
-			var snake Battlesnake = Battlesnake{
-				ID:     "1",
-				Name:   "Battlesnake",
-				Health: int32(100),
-				Body:   tt.SnakeCoords[1:],
-				Head:   tt.SnakeCoords[0],
-				Length: int32(len(tt.SnakeCoords)),
-				Shout:  "",
-			}
+			board, snake := mustParseBoard(t, tt.Board)
 
-			move := action.Execute(
-				snake,
-				Board{
-					Height: 10,
-					Width:  10,
-					Food:   tt.Food,
-					Snakes: []Battlesnake{snake},
-				},
-			)
+			move := action.Execute(context.Background(), snake, board)
 
 			if move != tt.Expected {
-				t.Errorf("Snake does not move in direcion of food (%s), %s instead", tt.Expected, move)
-				return
+				t.Errorf("Snake does not move in direction of food (%s), %s instead\n%s", tt.Expected, move, RenderBoard(board, snake.ID))
 			}
 		})
 	}
@@ -71,36 +94,89 @@ func TestCollectNearestFoodActionWithFoodInRange(t *testing.T) {
 
 func TestApproachBorder(t *testing.T) {
 	tests := []struct {
-		Name        string
-		Obstacles   []Coord
-		SnakeCoords []Coord
-		Expected    SnakeDirectionType
+		Name     string
+		Board    string
+		Expected SnakeDirectionType
 	}{
 		{
-			Name:        "With left border being closest, expect to go left",
-			SnakeCoords: []Coord{{X: 1, Y: 5}, {X: 1, Y: 6}},
-			Expected:    SnakeDirection.LEFT,
+			Name: "With left border being closest, expect to go left",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. v . . . . . . . .
+				. A . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.LEFT,
 		},
 		{
-			Name:        "With right border being closest, expect to go right",
-			SnakeCoords: []Coord{{X: 8, Y: 5}, {X: 8, Y: 6}},
-			Expected:    SnakeDirection.RIGHT,
+			Name: "With right border being closest, expect to go right",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . v .
+				. . . . . . . . A .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.RIGHT,
 		},
 		{
-			Name:        "With bottom border being closest, expect to go down",
-			SnakeCoords: []Coord{{X: 5, Y: 1}, {X: 6, Y: 1}},
-			Expected:    SnakeDirection.DOWN,
+			Name: "With bottom border being closest, expect to go down",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . A < . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.DOWN,
 		},
 		{
-			Name:        "With top border being closest, expect to go up",
-			SnakeCoords: []Coord{{X: 5, Y: 8}, {X: 6, Y: 8}},
-			Expected:    SnakeDirection.UP,
+			Name: "With top border being closest, expect to go up",
+			Board: `
+				. . . . . . . . . .
+				. . . . . A < . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.UP,
 		},
 		{
-			Name:        "Expect not to target obstacle above snake",
-			Obstacles:   []Coord{{X: 5, Y: 9}},
-			SnakeCoords: []Coord{{X: 5, Y: 8}, {X: 4, Y: 8}},
-			Expected:    SnakeDirection.RIGHT,
+			Name: "Expect not to target obstacle above snake",
+			Board: `
+				. . . . . B . . . .
+				. . . . > A . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.RIGHT,
 		},
 	}
 
@@ -108,50 +184,12 @@ func TestApproachBorder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
-			var snake Battlesnake = Battlesnake{
-				ID:     "1",
-				Name:   "Battlesnake",
-				Health: int32(100),
-				Body:   tt.SnakeCoords[1:],
-				Head:   tt.SnakeCoords[0],
-				Length: int32(len(tt.SnakeCoords)),
-				Shout:  "",
-			}
-
-			var battlesnakes []Battlesnake = []Battlesnake{snake}
-
-			if len(tt.Obstacles) > 0 {
-				var enemyBody []Coord
-				if len(tt.Obstacles) > 1 {
-					enemyBody = tt.Obstacles[1:]
-				}
-
-				var enemy = Battlesnake{
-					ID:     "2",
-					Name:   "Battlesnake 2",
-					Health: int32(100),
-					Body:   enemyBody,
-					Head:   tt.Obstacles[0],
-					Length: int32(len(tt.Obstacles)),
-					Shout:  "",
-				}
-
-				battlesnakes = append(battlesnakes, enemy)
-			}
+			board, snake := mustParseBoard(t, tt.Board)
 
-			move := action.Execute(
-				snake,
-				Board{
-					Height: 10,
-					Width:  10,
-					Food:   []Coord{},
-					Snakes: battlesnakes,
-				},
-			)
+			move := action.Execute(context.Background(), snake, board)
 
 			if move != tt.Expected {
-				t.Errorf("Snake does not move in direction of border (%s), %s instead", tt.Expected, move)
-				return
+				t.Errorf("Snake does not move in direction of border (%s), %s instead\n%s", tt.Expected, move, RenderBoard(board, snake.ID))
 			}
 		})
 	}
@@ -159,40 +197,105 @@ func TestApproachBorder(t *testing.T) {
 
 func TestFollowBorder(t *testing.T) {
 	tests := []struct {
-		Name        string
-		Obstacles   []Coord
-		SnakeCoords []Coord
-		Expected    SnakeDirectionType
+		Name     string
+		Board    string
+		Expected SnakeDirectionType
 	}{
 		{
-			Name:        "Expect to follow border when in top right corner (upwards)",
-			SnakeCoords: []Coord{{X: 9, Y: 9}, {X: 9, Y: 8}},
-			Expected:    SnakeDirection.LEFT,
+			Name: "Expect to follow border when in top right corner (upwards)",
+			Board: `
+				. . . . . . . . . A
+				. . . . . . . . . ^
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.LEFT,
 		},
 		{
-			Name:        "Expect to follow border when in top right corner (upwards)",
-			SnakeCoords: []Coord{{X: 9, Y: 9}, {X: 8, Y: 9}},
-			Expected:    SnakeDirection.DOWN,
+			Name: "Expect to follow border when in top right corner (rightwards)",
+			Board: `
+				. . . . . . . . > A
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.DOWN,
 		},
 		{
-			Name:        "Expect to follow border when next to top right corner",
-			SnakeCoords: []Coord{{X: 8, Y: 9}, {X: 7, Y: 9}, {X: 9, Y: 8}},
-			Expected:    SnakeDirection.RIGHT,
+			Name: "Expect to follow border when next to top right corner",
+			Board: `
+				. . . . . . > > A .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.RIGHT,
 		},
 		{
-			Name:        "Expect to change direction when in border anti-clockwise",
-			SnakeCoords: []Coord{{X: 8, Y: 9}, {X: 9, Y: 9}, {X: 9, Y: 8}},
-			Expected:    SnakeDirection.DOWN,
+			Name: "Expect to change direction when in border anti-clockwise",
+			Board: `
+				. . . . . . . . A <
+				. . . . . . . . . ^
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.DOWN,
 		},
 		{
-			Name:        "Expect to go down when in right border",
-			SnakeCoords: []Coord{{X: 9, Y: 8}, {X: 8, Y: 8}, {X: 8, Y: 9}},
-			Expected:    SnakeDirection.DOWN,
+			Name: "Expect to go down when in right border",
+			Board: `
+				. . . . . . . . v .
+				. . . . . . . . > A
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+			`,
+			Expected: SnakeDirection.DOWN,
 		},
 		{
-			Name:        "Expect to choose safe move when shortest path to border is blocked",
-			SnakeCoords: []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}},
-			Expected:    SnakeDirection.UP,
+			Name: "Expect to choose safe move when shortest path to border is blocked",
+			Board: `
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				. . . . . . . . . .
+				> A v . . . . . . .
+				^ < < . . . . . . .
+			`,
+			Expected: SnakeDirection.UP,
 		},
 	}
 
@@ -200,51 +303,32 @@ func TestFollowBorder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
-			var snake Battlesnake = Battlesnake{
-				ID:     "1",
-				Name:   "Battlesnake",
-				Health: int32(100),
-				Body:   tt.SnakeCoords[1:],
-				Head:   tt.SnakeCoords[0],
-				Length: int32(len(tt.SnakeCoords)),
-				Shout:  "",
-			}
-
-			var battlesnakes []Battlesnake = []Battlesnake{snake}
-
-			if len(tt.Obstacles) > 0 {
-				var enemyBody []Coord
-				if len(tt.Obstacles) > 1 {
-					enemyBody = tt.Obstacles[1:]
-				}
-
-				var enemy = Battlesnake{
-					ID:     "2",
-					Name:   "Battlesnake 2",
-					Health: int32(100),
-					Body:   enemyBody,
-					Head:   tt.Obstacles[0],
-					Length: int32(len(tt.Obstacles)),
-					Shout:  "",
-				}
-
-				battlesnakes = append(battlesnakes, enemy)
-			}
+			board, snake := mustParseBoard(t, tt.Board)
 
-			move := action.Execute(
-				snake,
-				Board{
-					Height: 10,
-					Width:  10,
-					Food:   []Coord{},
-					Snakes: battlesnakes,
-				},
-			)
+			move := action.Execute(context.Background(), snake, board)
 
 			if move != tt.Expected {
-				t.Errorf("Snake does not follow border (which is %s), but moves %s instead", tt.Expected, move)
-				return
+				t.Errorf("Snake does not follow border (which is %s), but moves %s instead\n%s", tt.Expected, move, RenderBoard(board, snake.ID))
 			}
 		})
 	}
 }
+
+func TestActionName(t *testing.T) {
+	tests := []struct {
+		Action   Action
+		Expected string
+	}{
+		{FollowBorder{}, "FollowBorder"},
+		{AvoidDeadEnds{ContestHeads{CollectNearestFood{}}}, "AvoidDeadEnds(ContestHeads(CollectNearestFood))"},
+		{AvoidDeadEnds{}, "AvoidDeadEnds"},
+		{&Minimax{}, "Minimax"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if name := ActionName(tt.Action); name != tt.Expected {
+			t.Errorf("Expected %q, got %q", tt.Expected, name)
+		}
+	}
+}
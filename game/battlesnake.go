@@ -1,13 +1,32 @@
 package game
 
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
 type Battlesnake struct {
 	ID     string  `json:"id"`
 	Name   string  `json:"name"`
 	Health int32   `json:"health"`
 	Body   []Coord `json:"body"`
-	Head   Coord   `json:"head"`
-	Length int32   `json:"length"`
-	Shout  string  `json:"shout"`
+	// Latency of the snake's last response in milliseconds, as reported by
+	// the engine.
+	Latency        string         `json:"latency"`
+	Head           Coord          `json:"head"`
+	Length         int32          `json:"length"`
+	Shout          string         `json:"shout"`
+	Squad          string         `json:"squad"`
+	Customizations Customizations `json:"customizations"`
+}
+
+// Customizations describe how a snake looks on the board.
+type Customizations struct {
+	Color string `json:"color"`
+	Head  string `json:"head"`
+	Tail  string `json:"tail"`
 }
 
 type StrategicBattlesnake struct {
@@ -15,3 +34,80 @@ type StrategicBattlesnake struct {
 	Strategy Strategy
 	Action   Action
 }
+
+type moveResult struct {
+	action Action
+	move   SnakeDirectionType
+	err    error
+}
+
+// NextMove lets the strategy choose an action for the current Snake and
+// executes it. Strategies are expected to return before ctx is done; if they
+// don't, or if they panic, a safe move is returned together with an error, so
+// that the snake always answers in time.
+func (strategic *StrategicBattlesnake) NextMove(ctx context.Context, board Board) (SnakeDirectionType, error) {
+	snake := strategic.Snake
+	strategy := strategic.Strategy
+	results := make(chan moveResult, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				results <- moveResult{err: fmt.Errorf("strategy panicked: %v\n%s", recovered, debug.Stack())}
+			}
+		}()
+		action := strategy.ExecuteNextStep(ctx, snake, board)
+		results <- moveResult{action: action, move: action.Execute(ctx, snake, board)}
+	}()
+
+	trace := traceFrom(ctx)
+	select {
+	case result := <-results:
+		if result.err != nil {
+			trace.fallback(StrategyName(strategy), "", "strategy panicked")
+			return getSafeMove(ctx, snake, board), result.err
+		}
+		strategic.Action = result.action
+		trace.choose(StrategyName(strategy), result.move, ActionName(result.action))
+		return result.move, nil
+	case <-ctx.Done():
+		trace.fallback(StrategyName(strategy), "", ctx.Err().Error())
+		return getSafeMove(ctx, snake, board), ctx.Err()
+	}
+}
+
+// searchReserve is the share of the remaining time that searches leave
+// unused, so that their result arrives before ctx is done. At least
+// minimumSearchReserve is left unless that is more than half of the time.
+const (
+	searchReserve        = 0.1
+	minimumSearchReserve = 10 * time.Millisecond
+)
+
+// withSearchDeadline returns a context for searches that run until they are
+// cancelled. It is done a little earlier than ctx.
+func withSearchDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	remaining := time.Until(deadline)
+	reserve := time.Duration(float64(remaining) * searchReserve)
+	if reserve < minimumSearchReserve {
+		reserve = minimumSearchReserve
+	}
+	if reserve > remaining/2 {
+		reserve = remaining / 2
+	}
+	return context.WithDeadline(ctx, deadline.Add(-reserve))
+}
+
+// isDone tells whether ctx is done. Unlike ctx.Err, it doesn't wait for the
+// timer of a context with deadline to fire, which may take a while when all
+// CPUs are busy searching.
+func isDone(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && !time.Now().Before(deadline)
+}
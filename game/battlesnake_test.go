@@ -0,0 +1,61 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type slowStrategy struct{}
+
+func (slowStrategy) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
+	time.Sleep(time.Second)
+	return MakeSafeMove{}
+}
+
+func TestNextMoveFallsBackToSafeMoveAfterDeadline(t *testing.T) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 90,
+		Head:   Coord{X: 0, Y: 9},
+		Body:   []Coord{{X: 0, Y: 8}},
+		Length: 2,
+	}
+	board := Board{Height: 10, Width: 10, Snakes: []Battlesnake{snake}}
+	strategic := StrategicBattlesnake{Snake: snake, Strategy: slowStrategy{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	move, err := strategic.NextMove(ctx, board)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline to be exceeded, got %v", err)
+	}
+	if move != SnakeDirection.RIGHT {
+		t.Errorf("Expected safe fallback move %s, got %s", SnakeDirection.RIGHT, move)
+	}
+}
+
+func TestNextMoveUsesStrategy(t *testing.T) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 90,
+		Head:   Coord{X: 2, Y: 2},
+		Body:   []Coord{{X: 2, Y: 1}},
+		Length: 2,
+	}
+	board := Board{Height: 10, Width: 10, Food: []Coord{{X: 1, Y: 2}}, Snakes: []Battlesnake{snake}}
+	strategic := StrategicBattlesnake{Snake: snake, Strategy: NearestFoodStrategy{}}
+
+	move, err := strategic.NextMove(context.Background(), board)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move != SnakeDirection.LEFT {
+		t.Errorf("Expected to move towards food (%s), got %s", SnakeDirection.LEFT, move)
+	}
+	if _, ok := strategic.Action.(CollectNearestFood); !ok {
+		t.Errorf("Expected action chosen by strategy to be kept, got %T", strategic.Action)
+	}
+}
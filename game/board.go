@@ -1,8 +1,12 @@
 package game
 
 type Board struct {
-	Height int           `json:"height"`
-	Width  int           `json:"width"`
-	Food   []Coord       `json:"food"`
-	Snakes []Battlesnake `json:"snakes"`
+	Height  int           `json:"height"`
+	Width   int           `json:"width"`
+	Food    []Coord       `json:"food"`
+	Hazards []Coord       `json:"hazards"`
+	Snakes  []Battlesnake `json:"snakes"`
+	// Ruleset is not part of the board sent by the engine, but copied from
+	// the game, so that strategies can adapt to the game mode being played.
+	Ruleset Ruleset `json:"-"`
 }
@@ -78,7 +78,7 @@ func (currentCoord Coord) isSafe(battlesnake Battlesnake, board Board) bool {
 	if currentCoord.isInSnakeTail(battlesnake) && battlesnake.Health < 100 {
 		return true
 	}
-	return !currentCoord.isOutsideOfArea(board) && !currentCoord.isInSnakes(board)
+	return !currentCoord.isOutsideOfArea(board) && !currentCoord.isInSnakes(board) && !currentCoord.isDeadlyHazard(battlesnake, board)
 }
 
 func abs(x int) int {
@@ -89,6 +89,10 @@ func abs(x int) int {
 }
 
 func (currentCoord Coord) isAtEdge(battlesnake Battlesnake, board Board) bool {
+	if !board.geometry().hasWalls() {
+		return false
+	}
+
 	if currentCoord.X == 0 || currentCoord.X == board.Width-1 {
 		return true
 	}
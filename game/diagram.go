@@ -0,0 +1,294 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Cells of board diagrams, as parsed by ParseBoard and written by RenderBoard.
+const (
+	diagramEmpty  = '.'
+	diagramFood   = '*'
+	diagramHazard = '~'
+)
+
+// diagramArrows point from a body segment to the next segment towards the
+// head.
+var diagramArrows = map[rune]SnakeDirectionType{
+	'^': SnakeDirection.UP,
+	'v': SnakeDirection.DOWN,
+	'<': SnakeDirection.LEFT,
+	'>': SnakeDirection.RIGHT,
+}
+
+// ParseBoard reads a board from a diagram with the top row first, e.g.
+//
+//	+-----------+
+//	| . . * . . |
+//	| . A < < . |
+//	| . . B ~ ~ |
+//	| . . ^ ~ ~ |
+//	+-----------+
+//	A: health=50
+//	B: id=enemy length=3
+//
+// Cells are '.' (empty), '*' (food), '~' (hazard), an upper case letter (the
+// head of a snake) or an arrow ('^', 'v', '<', '>') that points from a part of
+// a body to the next part towards the head. Spaces and the walls around the
+// board ('+', '-', '|') are optional.
+//
+// Lines below the board annotate snakes with their health (100 by default),
+// ID and name (the letter by default) and length, which stacks the tail if it
+// is longer than the snake on the board. "ruleset: wrapped" sets the ruleset.
+//
+// Snakes are added to the board in the order of their letters. The snake with
+// head A is returned as the snake to play.
+func ParseBoard(diagram string) (Board, Battlesnake, error) {
+	var rows [][]rune
+	annotations := map[string]string{}
+	for _, line := range strings.Split(diagram, "\n") {
+		if i := strings.Index(line, ":"); i >= 0 {
+			annotations[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+			continue
+		}
+		row := []rune(strings.Map(func(r rune) rune {
+			if strings.ContainsRune(" \t|-+", r) {
+				return -1
+			}
+			return r
+		}, line))
+		if len(row) == 0 {
+			continue
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return Board{}, Battlesnake{}, fmt.Errorf("row %d has %d cells instead of %d", len(rows)+1, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return Board{}, Battlesnake{}, fmt.Errorf("diagram contains no board")
+	}
+
+	board := Board{Height: len(rows), Width: len(rows[0]), Food: []Coord{}, Hazards: []Coord{}}
+	if name, ok := annotations["ruleset"]; ok {
+		board.Ruleset.Name = name
+		delete(annotations, "ruleset")
+	}
+
+	cells := map[Coord]rune{}
+	var heads []rune
+	for i, row := range rows {
+		for x, cell := range row {
+			coord := Coord{x, board.Height - 1 - i}
+			switch _, isArrow := diagramArrows[cell]; {
+			case cell == diagramEmpty:
+			case cell == diagramFood:
+				board.Food = append(board.Food, coord)
+			case cell == diagramHazard:
+				board.Hazards = append(board.Hazards, coord)
+			case cell >= 'A' && cell <= 'Z':
+				if _, ok := annotations[string(cell)]; !ok {
+					annotations[string(cell)] = ""
+				}
+				heads = append(heads, cell)
+				cells[coord] = cell
+			case isArrow:
+				cells[coord] = cell
+			default:
+				return Board{}, Battlesnake{}, fmt.Errorf("unknown cell %q at %d,%d", cell, coord.X, coord.Y)
+			}
+		}
+	}
+	sort.Slice(heads, func(i, j int) bool { return heads[i] < heads[j] })
+
+	claimed := map[Coord]bool{}
+	for i, letter := range heads {
+		if i > 0 && heads[i-1] == letter {
+			return Board{}, Battlesnake{}, fmt.Errorf("snake %c has more than one head", letter)
+		}
+		snake, err := parseSnake(letter, cells, claimed, board)
+		if err != nil {
+			return Board{}, Battlesnake{}, err
+		}
+		if err := annotateSnake(&snake, annotations[string(letter)]); err != nil {
+			return Board{}, Battlesnake{}, fmt.Errorf("snake %c: %v", letter, err)
+		}
+		delete(annotations, string(letter))
+		board.Snakes = append(board.Snakes, snake)
+	}
+	for coord, cell := range cells {
+		if !claimed[coord] {
+			return Board{}, Battlesnake{}, fmt.Errorf("%q at %d,%d does not lead to a head", cell, coord.X, coord.Y)
+		}
+	}
+	for key := range annotations {
+		return Board{}, Battlesnake{}, fmt.Errorf("annotation for unknown snake %q", key)
+	}
+
+	if len(heads) == 0 || heads[0] != 'A' {
+		return board, Battlesnake{}, fmt.Errorf("diagram contains no snake A")
+	}
+	return board, board.Snakes[0], nil
+}
+
+// parseSnake follows the arrows that point to the head with the given letter,
+// from the head to the tail.
+func parseSnake(letter rune, cells map[Coord]rune, claimed map[Coord]bool, board Board) (Battlesnake, error) {
+	var head Coord
+	for coord, cell := range cells {
+		if cell == letter {
+			head = coord
+		}
+	}
+	claimed[head] = true
+
+	body := []Coord{head}
+	for current := head; ; {
+		var previous []Coord
+		for _, move := range possibleMoves {
+			coord := current.neighbor(move, board)
+			direction, isArrow := diagramArrows[cells[coord]]
+			if isArrow && !claimed[coord] && coord.neighbor(direction, board).equals(current) {
+				previous = append(previous, coord)
+			}
+		}
+		if len(previous) == 0 {
+			break
+		}
+		if len(previous) > 1 {
+			return Battlesnake{}, fmt.Errorf("snake %c continues at more than one cell after %d,%d", letter, current.X, current.Y)
+		}
+		current = previous[0]
+		claimed[current] = true
+		body = append(body, current)
+	}
+
+	return Battlesnake{
+		ID:     string(letter),
+		Name:   string(letter),
+		Health: maxHealth,
+		Head:   head,
+		Body:   body,
+		Length: int32(len(body)),
+	}, nil
+}
+
+// annotateSnake applies annotations like "health=50 length=4 id=me".
+func annotateSnake(snake *Battlesnake, annotation string) error {
+	for _, field := range strings.Fields(annotation) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid annotation %q", field)
+		}
+		key, value := parts[0], parts[1]
+		switch key {
+		case "id":
+			snake.ID = value
+		case "name":
+			snake.Name = value
+		case "health", "length":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "health" {
+				snake.Health = int32(number)
+				continue
+			}
+			if number < len(snake.Body) {
+				return fmt.Errorf("length %d is shorter than the snake on the board", number)
+			}
+			for len(snake.Body) < number {
+				snake.Body = append(snake.Body, snake.Body[len(snake.Body)-1])
+			}
+			snake.Length = int32(number)
+		default:
+			return fmt.Errorf("unknown annotation %q", key)
+		}
+	}
+	return nil
+}
+
+// RenderBoard draws the board as a diagram that ParseBoard can read. The snake
+// with the given ID is drawn as A, the others get the following letters in
+// the order of the board. Snakes hide food and hazards below them. Snakes
+// beyond the 26th are drawn as ?, so boards with more snakes can be looked at,
+// but not parsed again.
+func RenderBoard(board Board, youID string) string {
+	rows := make([][]rune, board.Height)
+	for i := range rows {
+		rows[i] = []rune(strings.Repeat(string(diagramEmpty), board.Width))
+	}
+	draw := func(coord Coord, cell rune) {
+		if !coord.isOutsideOfArea(board) {
+			rows[board.Height-1-coord.Y][coord.X] = cell
+		}
+	}
+	for _, hazard := range board.Hazards {
+		draw(hazard, diagramHazard)
+	}
+	for _, food := range board.Food {
+		draw(food, diagramFood)
+	}
+
+	var snakes []Battlesnake
+	for _, snake := range board.Snakes {
+		if snake.ID == youID {
+			snakes = append([]Battlesnake{snake}, snakes...)
+		} else {
+			snakes = append(snakes, snake)
+		}
+	}
+
+	var annotations strings.Builder
+	for i, snake := range snakes {
+		letter := rune('A' + i)
+		if letter > 'Z' {
+			letter = '?'
+		}
+		segments := snake.segments()
+		for j := len(segments) - 1; j > 0; j-- {
+			if segments[j].equals(segments[j-1]) {
+				continue
+			}
+			draw(segments[j], diagramArrow(segments[j], segments[j-1], board))
+		}
+		draw(snake.Head, letter)
+
+		fmt.Fprintf(&annotations, "%c: health=%d length=%d", letter, snake.Health, snake.Length)
+		if snake.ID != string(letter) {
+			fmt.Fprintf(&annotations, " id=%s", snake.ID)
+		}
+		annotations.WriteString("\n")
+	}
+
+	var diagram strings.Builder
+	wall := "+" + strings.Repeat("-", 2*board.Width+1) + "+\n"
+	diagram.WriteString(wall)
+	for _, row := range rows {
+		diagram.WriteString("|")
+		for _, cell := range row {
+			diagram.WriteString(" " + string(cell))
+		}
+		diagram.WriteString(" |\n")
+	}
+	diagram.WriteString(wall)
+	if board.Ruleset.Name != "" {
+		fmt.Fprintf(&diagram, "ruleset: %s\n", board.Ruleset.Name)
+	}
+	diagram.WriteString(annotations.String())
+	return diagram.String()
+}
+
+// diagramArrow returns the arrow that points from a segment to the next one,
+// or '?' if they are not neighbors.
+func diagramArrow(from Coord, to Coord, board Board) rune {
+	for arrow, move := range diagramArrows {
+		if from.neighbor(move, board).equals(to) {
+			return arrow
+		}
+	}
+	return '?'
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mustParseBoard parses a diagram and fails the test if it is invalid.
+func mustParseBoard(t *testing.T, diagram string) (Board, Battlesnake) {
+	t.Helper()
+	board, snake, err := ParseBoard(diagram)
+	if err != nil {
+		t.Fatalf("Invalid diagram: %v", err)
+	}
+	return board, snake
+}
+
+func TestParseBoard(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		+-----------+
+		| . . * . . |
+		| . A < < . |
+		| . . B ~ ~ |
+		| > > ^ ~ ~ |
+		+-----------+
+		A: health=50
+		B: id=enemy length=5
+		ruleset: wrapped
+	`)
+
+	expected := Board{
+		Height:  4,
+		Width:   5,
+		Food:    []Coord{{2, 3}},
+		Hazards: []Coord{{3, 1}, {4, 1}, {3, 0}, {4, 0}},
+		Snakes: []Battlesnake{
+			{ID: "A", Name: "A", Health: 50, Head: Coord{1, 2}, Body: []Coord{{1, 2}, {2, 2}, {3, 2}}, Length: 3},
+			{ID: "enemy", Name: "B", Health: 100, Head: Coord{2, 1}, Body: []Coord{{2, 1}, {2, 0}, {1, 0}, {0, 0}, {0, 0}}, Length: 5},
+		},
+		Ruleset: Ruleset{Name: RulesetWrapped},
+	}
+	if !reflect.DeepEqual(board, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, board)
+	}
+	if !reflect.DeepEqual(snake, expected.Snakes[0]) {
+		t.Errorf("Expected snake A, got %+v", snake)
+	}
+}
+
+func TestParseBoardFollowsArrowsAcrossWrappedEdges(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		A . >
+		ruleset: wrapped
+	`)
+
+	expected := []Coord{{0, 0}, {2, 0}}
+	if !reflect.DeepEqual(snake.Body, expected) {
+		t.Errorf("Expected body %v, got %v\n%s", expected, snake.Body, RenderBoard(board, snake.ID))
+	}
+}
+
+func TestParseBoardErrors(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Diagram string
+	}{
+		{"Empty", ""},
+		{"Rows of different width", "A . .\n. ."},
+		{"Unknown cell", "A x"},
+		{"Missing snake A", "B < ."},
+		{"Two heads", "A . A"},
+		{"Arrow not leading to a head", "A . >"},
+		{"Ambiguous body", "> A <"},
+		{"Annotation of unknown snake", "A\nB: health=10"},
+		{"Unknown annotation", "A\nA: speed=10"},
+		{"Invalid health", "A\nA: health=full"},
+		{"Length shorter than the board", "A <\nA: length=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if _, _, err := ParseBoard(tt.Diagram); err == nil {
+				t.Errorf("Expected an error for %q", tt.Diagram)
+			}
+		})
+	}
+}
+
+func TestRenderBoardWithMoreSnakesThanLetters(t *testing.T) {
+	board := Board{Height: 27, Width: 1}
+	for y := 0; y < board.Height; y++ {
+		board.Snakes = append(board.Snakes, Battlesnake{ID: fmt.Sprint(y), Health: 100, Head: Coord{0, y}, Body: []Coord{{0, y}}, Length: 1})
+	}
+
+	diagram := RenderBoard(board, "0")
+
+	if !strings.Contains(diagram, "| Z |") || !strings.Contains(diagram, "| ? |") || !strings.Contains(diagram, "?: health=100 length=1 id=26") {
+		t.Errorf("Expected the 27th snake to be drawn as ?, got\n%s", diagram)
+	}
+	if _, _, err := ParseBoard(diagram); err == nil {
+		t.Errorf("Expected a diagram with more than 26 snakes not to parse")
+	}
+}
+
+func TestRenderBoard(t *testing.T) {
+	board := Board{
+		Height:  3,
+		Width:   4,
+		Food:    []Coord{{0, 2}},
+		Hazards: []Coord{{3, 0}},
+		Snakes: []Battlesnake{
+			{ID: "enemy", Health: 80, Head: Coord{3, 2}, Body: []Coord{{3, 2}, {3, 1}}, Length: 2},
+			{ID: "me", Health: 100, Head: Coord{1, 1}, Body: []Coord{{1, 1}, {1, 0}, {0, 0}, {0, 0}}, Length: 4},
+		},
+	}
+
+	expected := `+---------+
+| * . . B |
+| . A . ^ |
+| > ^ . ~ |
++---------+
+A: health=100 length=4 id=me
+B: health=80 length=2 id=enemy
+`
+	diagram := RenderBoard(board, "me")
+	if diagram != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, diagram)
+	}
+
+	parsed, snake := mustParseBoard(t, diagram)
+	if snake.ID != "me" || !reflect.DeepEqual(snake.Body, board.Snakes[1].Body) || len(parsed.Snakes) != 2 {
+		t.Errorf("Rendered board does not parse to the same board:\n%s", RenderBoard(parsed, snake.ID))
+	}
+}
@@ -0,0 +1,294 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Evaluator scores a board from the point of view of the snake with the given
+// ID. Higher scores are better.
+type Evaluator interface {
+	Evaluate(board Board, snakeID string) float64
+}
+
+// EvaluationFunc is an ordinary function used as Evaluator.
+type EvaluationFunc func(board Board, snakeID string) float64
+
+func (evaluate EvaluationFunc) Evaluate(board Board, snakeID string) float64 {
+	return evaluate(board, snakeID)
+}
+
+// Names of the evaluation terms, as used in Weights. All terms are measured
+// in moves, cells or health points and expect the snake to be on the board.
+const (
+	// TermHealth is the snake's health.
+	TermHealth = "health"
+	// TermLength is how much longer the snake is than its longest opponent.
+	TermLength = "length"
+	// TermArea is the largest area the snake can reach with its next move.
+	// It is minus the size of the board if the snake is trapped.
+	TermArea = "area"
+	// TermTerritory is the number of cells the snake reaches before all
+	// opponents.
+	TermTerritory = "territory"
+	// TermFood is minus the distance to the nearest food.
+	TermFood = "food"
+	// TermCenter is minus the distance to the center of the board.
+	TermCenter = "center"
+	// TermHazard is minus the number of hazards the snake's head is in.
+	TermHazard = "hazard"
+	// TermHeadToHead is the number of cells next to the snake's head where
+	// it can win a head-to-head collision, minus those where it can lose one.
+	TermHeadToHead = "head-to-head"
+	// TermOpponents is the number of opponents left.
+	TermOpponents = "opponents"
+)
+
+var terms = map[string]EvaluationFunc{
+	TermHealth:     evaluateHealth,
+	TermLength:     evaluateLength,
+	TermArea:       evaluateArea,
+	TermTerritory:  evaluateTerritory,
+	TermFood:       evaluateFood,
+	TermCenter:     evaluateCenter,
+	TermHazard:     evaluateHazard,
+	TermHeadToHead: evaluateHeadToHead,
+	TermOpponents:  evaluateOpponents,
+}
+
+// Weights maps names of evaluation terms to their weights.
+type Weights map[string]float64
+
+// DefaultWeights are used by strategies that are not given an Evaluator. They
+// prefer boards on which the snake has much space, is longer than its
+// opponents and has health left.
+var DefaultWeights = Weights{
+	TermArea:      1,
+	TermLength:    10,
+	TermHealth:    0.1,
+	TermOpponents: -5,
+}
+
+// LoadWeights reads weights from a JSON file that maps names of terms to
+// their weights, e.g. {"area": 1, "length": 10}.
+func LoadWeights(path string) (Weights, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var weights Weights
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return nil, fmt.Errorf("invalid weights in %s: %v", path, err)
+	}
+	if _, err := weights.Evaluator(); err != nil {
+		return nil, fmt.Errorf("invalid weights in %s: %v", path, err)
+	}
+	return weights, nil
+}
+
+// Evaluator returns an Evaluator that sums up the terms multiplied by their
+// weights.
+func (weights Weights) Evaluator() (WeightedSum, error) {
+	var names []string
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sum WeightedSum
+	for _, name := range names {
+		term, ok := terms[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown term %q, available are %s", name, strings.Join(TermNames(), ", "))
+		}
+		sum = append(sum, WeightedTerm{Weight: weights[name], Term: term})
+	}
+	return sum, nil
+}
+
+// TermNames returns the names of all evaluation terms known to Weights.
+func TermNames() []string {
+	var names []string
+	for name := range terms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WeightedTerm is an Evaluator that is part of a WeightedSum.
+type WeightedTerm struct {
+	Weight float64
+	Term   Evaluator
+}
+
+// WeightedSum is an Evaluator that adds up the scores of its terms multiplied
+// by their weights. Boards on which the snake was eliminated score lossScore.
+type WeightedSum []WeightedTerm
+
+func (sum WeightedSum) Evaluate(board Board, snakeID string) float64 {
+	if _, ok := board.snake(snakeID); !ok {
+		return lossScore
+	}
+	score := 0.0
+	for _, term := range sum {
+		if term.Weight != 0 {
+			score += term.Weight * term.Term.Evaluate(board, snakeID)
+		}
+	}
+	return score
+}
+
+var defaultEvaluator, _ = DefaultWeights.Evaluator()
+
+func evaluateHealth(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	return float64(snake.Health)
+}
+
+func evaluateLength(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	var longestOpponent int32
+	for _, other := range board.Snakes {
+		if other.ID != snakeID && other.Length > longestOpponent {
+			longestOpponent = other.Length
+		}
+	}
+	return float64(snake.Length - longestOpponent)
+}
+
+func evaluateArea(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	largestArea := 0
+	for _, area := range reachableAreaPerMove(snake, board) {
+		if area > largestArea {
+			largestArea = area
+		}
+	}
+	if largestArea == 0 {
+		return -float64(board.Width * board.Height)
+	}
+	return float64(largestArea)
+}
+
+func evaluateTerritory(board Board, snakeID string) float64 {
+	return float64(Territories(board)[snakeID].Cells)
+}
+
+func evaluateFood(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	nearest := 0
+	for i, food := range board.Food {
+		if distance := snake.Head.distanceOn(food, board); i == 0 || distance < nearest {
+			nearest = distance
+		}
+	}
+	return -float64(nearest)
+}
+
+func evaluateCenter(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	center := Coord{board.Width / 2, board.Height / 2}
+	return -float64(snake.Head.distanceOn(center, board))
+}
+
+func evaluateHazard(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	return -float64(snake.Head.hazardCount(board))
+}
+
+func evaluateHeadToHead(board Board, snakeID string) float64 {
+	snake, _ := board.snake(snakeID)
+	risks := headToHeadRisks(snake, board)
+	score := 0
+	for _, move := range possibleMoves {
+		switch risks[snake.Head.neighbor(move, board)] {
+		case winningHeadToHead:
+			score++
+		case losingHeadToHead:
+			score--
+		}
+	}
+	return float64(score)
+}
+
+func evaluateOpponents(board Board, snakeID string) float64 {
+	return float64(len(board.Snakes) - 1)
+}
+
+// Greedy is a Strategy that looks one move ahead and makes the safe move after
+// which Evaluator scores the board best. The opponents are assumed to stay
+// where they are.
+type Greedy struct {
+	// Evaluator scores the boards after each move. DefaultWeights are used
+	// if it is nil.
+	Evaluator Evaluator
+}
+
+func (greedy Greedy) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
+	return greedy
+}
+
+func (greedy Greedy) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	evaluator := greedy.Evaluator
+	if evaluator == nil {
+		evaluator = defaultEvaluator
+	}
+
+	trace := traceFrom(ctx)
+	bestMove := SnakeDirectionType("")
+	bestScore := 0.0
+	for _, move := range possibleMoves {
+		if newCoord := snake.Head.neighbor(move, board); !newCoord.isSafe(snake, board) {
+			trace.reject("Greedy", move, newCoord.unsafeReason(snake, board))
+			continue
+		}
+		score := evaluator.Evaluate(moveSnake(board, snake.ID, move), snake.ID)
+		trace.score("Greedy", move, score, "")
+		if bestMove == "" || score > bestScore {
+			bestMove = move
+			bestScore = score
+		}
+	}
+	if bestMove == "" {
+		return getSafeMove(ctx, snake, board)
+	}
+	return bestMove
+}
+
+// moveSnake returns the board after only the snake with the given ID made the
+// move, including the loss of health, hazard damage and eating.
+func moveSnake(board Board, snakeID string, move SnakeDirectionType) Board {
+	next := board.clone()
+	for i := range next.Snakes {
+		snake := &next.Snakes[i]
+		if snake.ID != snakeID {
+			continue
+		}
+		head := snake.Head.neighbor(move, next)
+		snake.Body = append([]Coord{head}, snake.Body[:len(snake.Body)-1]...)
+		snake.Head = head
+		snake.Health -= head.healthCost(next)
+
+		var remainingFood []Coord
+		for _, food := range next.Food {
+			if !food.equals(head) {
+				remainingFood = append(remainingFood, food)
+				continue
+			}
+			snake.Health = maxHealth
+			snake.Body = append(snake.Body, snake.Body[len(snake.Body)-1])
+			snake.Length = int32(len(snake.Body))
+		}
+		next.Food = remainingFood
+		if snake.Health <= 0 {
+			next.Snakes = append(next.Snakes[:i], next.Snakes[i+1:]...)
+		}
+		break
+	}
+	return next
+}
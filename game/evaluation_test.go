@@ -0,0 +1,127 @@
+package game
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createEvaluationBoard() Board {
+	snake := Battlesnake{ID: "1", Health: 80, Head: Coord{X: 1, Y: 1}, Body: []Coord{{X: 1, Y: 0}, {X: 0, Y: 0}}, Length: 3}
+	opponent := Battlesnake{ID: "2", Health: 90, Head: Coord{X: 3, Y: 1}, Body: []Coord{{X: 4, Y: 1}}, Length: 2}
+	return Board{
+		Height:  5,
+		Width:   5,
+		Food:    []Coord{{X: 4, Y: 4}, {X: 1, Y: 3}},
+		Hazards: []Coord{{X: 1, Y: 1}},
+		Snakes:  []Battlesnake{snake, opponent},
+	}
+}
+
+func TestEvaluationTerms(t *testing.T) {
+	board := createEvaluationBoard()
+
+	tests := []struct {
+		Term     string
+		Expected float64
+	}{
+		{TermHealth, 80},
+		{TermLength, 1},
+		{TermArea, 25},
+		{TermFood, -2},
+		{TermCenter, -2},
+		{TermHazard, -1},
+		{TermHeadToHead, 1},
+		{TermOpponents, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Term, func(t *testing.T) {
+			if score := terms[tt.Term].Evaluate(board, "1"); score != tt.Expected {
+				t.Errorf("Expected %v, got %v", tt.Expected, score)
+			}
+		})
+	}
+}
+
+func TestWeightedSum(t *testing.T) {
+	board := createEvaluationBoard()
+	evaluator, err := Weights{TermHealth: 0.5, TermLength: 10}.Evaluator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if score := evaluator.Evaluate(board, "1"); score != 50 {
+		t.Errorf("Expected 0.5 * 80 + 10 * 1, got %v", score)
+	}
+	if score := evaluator.Evaluate(board, "3"); score != lossScore {
+		t.Errorf("Expected eliminated snake to score %v, got %v", lossScore, score)
+	}
+	if _, err := (Weights{"luck": 1}).Evaluator(); err == nil {
+		t.Errorf("Expected unknown term to be rejected")
+	}
+}
+
+func TestLoadWeights(t *testing.T) {
+	dir, err := ioutil.TempDir("", "weights")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	valid := filepath.Join(dir, "valid.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(valid, []byte(`{"area": 1, "head-to-head": 2.5}`), 0644)
+	ioutil.WriteFile(invalid, []byte(`{"luck": 1}`), 0644)
+
+	weights, err := LoadWeights(valid)
+	if err != nil || weights[TermArea] != 1 || weights[TermHeadToHead] != 2.5 {
+		t.Errorf("Expected weights to be loaded, got %v and %v", weights, err)
+	}
+	if _, err := LoadWeights(invalid); err == nil {
+		t.Errorf("Expected unknown term to be rejected")
+	}
+	if _, err := LoadWeights(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Expected missing file to be rejected")
+	}
+}
+
+func TestGreedy(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 50, Head: Coord{X: 5, Y: 5}, Body: []Coord{{X: 5, Y: 4}, {X: 5, Y: 3}}, Length: 3}
+	board := Board{Height: 11, Width: 11, Food: []Coord{{X: 2, Y: 5}}, Hazards: []Coord{{X: 4, Y: 5}, {X: 5, Y: 6}}, Snakes: []Battlesnake{snake}}
+
+	tests := []struct {
+		Name     string
+		Weights  Weights
+		Expected SnakeDirectionType
+	}{
+		{"Expect to approach food", Weights{TermFood: 1}, SnakeDirection.LEFT},
+		{"Expect to avoid hazards on the way to food", Weights{TermFood: 1, TermHazard: 10}, SnakeDirection.RIGHT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			evaluator, err := tt.Weights.Evaluator()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			move := Greedy{Evaluator: evaluator}.Execute(context.Background(), snake, board)
+
+			if move != tt.Expected {
+				t.Errorf("Expected %s, got %s", tt.Expected, move)
+			}
+		})
+	}
+}
+
+func TestMCTSWithEvaluator(t *testing.T) {
+	snake, board := createPocketBoard()
+
+	move := MCTS{Iterations: 300, RolloutDepth: 5, Seed: 1, Evaluator: defaultEvaluator}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.RIGHT {
+		t.Errorf("Expected to avoid dead end (%s), got %s", SnakeDirection.RIGHT, move)
+	}
+}
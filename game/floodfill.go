@@ -0,0 +1,112 @@
+package game
+
+import (
+	"context"
+	"fmt"
+)
+
+// vacatingTimes returns for every cell occupied by a snake the number of moves
+// after which the cell is free again, because the snake's tail has moved past
+// it. A snake that has just eaten keeps its tail for one more turn. Boards of
+// the engine already show this by a doubled tail segment, boards built by hand
+// may not.
+func vacatingTimes(board Board) map[Coord]int {
+	times := map[Coord]int{}
+	for _, snake := range board.Snakes {
+		segments := snake.segments()
+		growth := 0
+		stacked := len(segments) > 1 && segments[len(segments)-1].equals(segments[len(segments)-2])
+		if snake.Health == maxHealth && !stacked {
+			growth = 1
+		}
+		for i := len(segments) - 1; i >= 0; i-- {
+			times[segments[i]] = len(segments) - i + growth
+		}
+	}
+	return times
+}
+
+// reachableArea counts the cells a snake can reach when its head moves onto
+// start in the next turn. Cells occupied by snakes become reachable once
+// their tails have moved past them.
+func reachableArea(board Board, start Coord) int {
+	if start.isOutsideOfArea(board) {
+		return 0
+	}
+	vacating := vacatingTimes(board)
+	if vacating[start] > 1 {
+		return 0
+	}
+
+	visited := map[Coord]bool{start: true}
+	queue := []Coord{start}
+	depths := map[Coord]int{start: 1}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, move := range possibleMoves {
+			next := current.neighbor(move, board)
+			if visited[next] || next.isOutsideOfArea(board) || vacating[next] > depths[current]+1 {
+				continue
+			}
+			visited[next] = true
+			depths[next] = depths[current] + 1
+			queue = append(queue, next)
+		}
+	}
+	return len(visited)
+}
+
+// reachableAreaPerMove returns the reachable area for every safe move of the
+// snake.
+func reachableAreaPerMove(battlesnake Battlesnake, board Board) map[SnakeDirectionType]int {
+	areas := map[SnakeDirectionType]int{}
+	for _, move := range possibleMoves {
+		newCoord := battlesnake.Head.neighbor(move, board)
+		if newCoord.isSafe(battlesnake, board) {
+			areas[move] = reachableArea(board, newCoord)
+		}
+	}
+	return areas
+}
+
+// AvoidDeadEnds executes Action, but only keeps its move if the snake fits
+// into the area it can still reach afterwards. Otherwise the safe move leading
+// into the largest area is made. Without an Action, the move into the largest
+// area is always chosen.
+type AvoidDeadEnds struct {
+	Action Action
+}
+
+func (avoid AvoidDeadEnds) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	trace := traceFrom(ctx)
+	areas := reachableAreaPerMove(snake, board)
+	if avoid.Action != nil {
+		move := avoid.Action.Execute(ctx, snake, board)
+		area, ok := areas[move]
+		if ok && area >= int(snake.Length) {
+			return move
+		}
+		if !ok {
+			trace.reject("AvoidDeadEnds", move, "unsafe")
+		} else {
+			trace.reject("AvoidDeadEnds", move, fmt.Sprintf("dead end of %d cells", area))
+		}
+	}
+
+	bestMove := SnakeDirectionType("")
+	for _, move := range possibleMoves {
+		area, ok := areas[move]
+		if ok {
+			trace.score("AvoidDeadEnds", move, float64(area), "reachable cells")
+		}
+		if ok && (bestMove == "" || area > areas[bestMove]) {
+			bestMove = move
+		}
+	}
+	if bestMove == "" {
+		return getSafeMove(ctx, snake, board)
+	}
+	trace.choose("AvoidDeadEnds", bestMove, "largest area")
+	return bestMove
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type fixedMove SnakeDirectionType
+
+func (move fixedMove) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	return SnakeDirectionType(move)
+}
+
+// createPocketBoard returns a board on which the snake can move left into a
+// pocket of four cells that is too small for its length of eight.
+func createPocketBoard() (Battlesnake, Board) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 90,
+		Head:   Coord{X: 2, Y: 1},
+		Body:   []Coord{{X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 6, Y: 0}, {X: 6, Y: 1}, {X: 6, Y: 2}},
+		Length: 8,
+	}
+	enemy := Battlesnake{
+		ID:     "2",
+		Health: 90,
+		Head:   Coord{X: 2, Y: 2},
+		Body:   []Coord{{X: 1, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 3}, {X: 0, Y: 4}, {X: 0, Y: 5}, {X: 0, Y: 6}, {X: 1, Y: 6}},
+		Length: 8,
+	}
+	return snake, Board{Height: 7, Width: 7, Food: []Coord{}, Snakes: []Battlesnake{snake, enemy}}
+}
+
+func TestReachableAreaPerMove(t *testing.T) {
+	snake, board := createPocketBoard()
+
+	areas := reachableAreaPerMove(snake, board)
+
+	if len(areas) != 2 {
+		t.Fatalf("Expected only left and right to be safe, got %v", areas)
+	}
+	if areas[SnakeDirection.LEFT] != 4 {
+		t.Errorf("Expected pocket on the left to have 4 cells, got %d", areas[SnakeDirection.LEFT])
+	}
+	if areas[SnakeDirection.RIGHT] < int(snake.Length) {
+		t.Errorf("Expected enough space on the right, got %d", areas[SnakeDirection.RIGHT])
+	}
+}
+
+func TestReachableAreaCountsVacatingTails(t *testing.T) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 90,
+		Head:   Coord{X: 1, Y: 0},
+		Body:   []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}},
+		Length: 3,
+	}
+	board := Board{Height: 2, Width: 2, Snakes: []Battlesnake{snake}}
+
+	if area := reachableArea(board, Coord{X: 0, Y: 0}); area != 4 {
+		t.Errorf("Expected whole board to be reachable by following the tail, got %d", area)
+	}
+}
+
+func TestVacatingTimes(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Snake    Battlesnake
+		Expected map[Coord]int
+	}{
+		{
+			Name:     "Expect tail to move away",
+			Snake:    Battlesnake{Health: 90, Head: Coord{X: 1, Y: 0}, Body: []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+			Expected: map[Coord]int{{X: 0, Y: 1}: 1, {X: 1, Y: 1}: 2, {X: 1, Y: 0}: 3},
+		},
+		{
+			Name:     "Expect doubled tail of snake that has just eaten to stay once",
+			Snake:    Battlesnake{Health: maxHealth, Head: Coord{X: 1, Y: 0}, Body: []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 1}}},
+			Expected: map[Coord]int{{X: 0, Y: 1}: 2, {X: 1, Y: 1}: 3, {X: 1, Y: 0}: 4},
+		},
+		{
+			Name:     "Expect tail of snake that has just eaten to stay once without doubled tail",
+			Snake:    Battlesnake{Health: maxHealth, Head: Coord{X: 1, Y: 0}, Body: []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}}},
+			Expected: map[Coord]int{{X: 0, Y: 1}: 2, {X: 1, Y: 1}: 3, {X: 1, Y: 0}: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		board := Board{Height: 2, Width: 2, Snakes: []Battlesnake{tt.Snake}}
+
+		if times := vacatingTimes(board); !reflect.DeepEqual(times, tt.Expected) {
+			t.Errorf("%s: expected %v, got %v", tt.Name, tt.Expected, times)
+		}
+	}
+}
+
+func TestAvoidDeadEnds(t *testing.T) {
+	snake, board := createPocketBoard()
+
+	tests := []struct {
+		Name     string
+		Action   Action
+		Expected SnakeDirectionType
+	}{
+		{
+			Name:     "Expect to avoid pocket that is too small",
+			Action:   fixedMove(SnakeDirection.LEFT),
+			Expected: SnakeDirection.RIGHT,
+		},
+		{
+			Name:     "Expect to keep move into open space",
+			Action:   fixedMove(SnakeDirection.RIGHT),
+			Expected: SnakeDirection.RIGHT,
+		},
+		{
+			Name:     "Expect to move into largest area without action",
+			Expected: SnakeDirection.RIGHT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			move := AvoidDeadEnds{tt.Action}.Execute(context.Background(), snake, board)
+			if move != tt.Expected {
+				t.Errorf("Snake does not avoid dead end (expected %s), but moves %s instead", tt.Expected, move)
+			}
+		})
+	}
+}
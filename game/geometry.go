@@ -0,0 +1,80 @@
+package game
+
+// geometry describes how the cells of a board are connected.
+type geometry interface {
+	// neighbor returns the cell reached from coord by making the move. The
+	// result may be outside of the board if the board has walls.
+	neighbor(coord Coord, move SnakeDirectionType) Coord
+	// distance returns the least number of moves between the two cells,
+	// ignoring any obstacles.
+	distance(from Coord, to Coord) int
+	// hasWalls tells whether snakes can leave the board.
+	hasWalls() bool
+}
+
+// boundedGeometry is the grid of the standard rules, surrounded by walls.
+type boundedGeometry struct{}
+
+func (boundedGeometry) neighbor(coord Coord, move SnakeDirectionType) Coord {
+	return coord.newCoordFromMove(move)
+}
+
+func (boundedGeometry) distance(from Coord, to Coord) int {
+	return from.distanceToOther(to)
+}
+
+func (boundedGeometry) hasWalls() bool {
+	return true
+}
+
+// wrappedGeometry is the grid of the wrapped rules, where leaving the board at
+// one edge enters it at the opposite edge.
+type wrappedGeometry struct {
+	width  int
+	height int
+}
+
+func (wrapped wrappedGeometry) neighbor(coord Coord, move SnakeDirectionType) Coord {
+	next := coord.newCoordFromMove(move)
+	return Coord{mod(next.X, wrapped.width), mod(next.Y, wrapped.height)}
+}
+
+func (wrapped wrappedGeometry) distance(from Coord, to Coord) int {
+	dx := abs(from.X - to.X)
+	dy := abs(from.Y - to.Y)
+	if wrapped.width-dx < dx {
+		dx = wrapped.width - dx
+	}
+	if wrapped.height-dy < dy {
+		dy = wrapped.height - dy
+	}
+	return dx + dy
+}
+
+func (wrappedGeometry) hasWalls() bool {
+	return false
+}
+
+// geometry returns the geometry used by the board's ruleset.
+func (board Board) geometry() geometry {
+	if board.Ruleset.Name == RulesetWrapped && board.Width > 0 && board.Height > 0 {
+		return wrappedGeometry{board.Width, board.Height}
+	}
+	return boundedGeometry{}
+}
+
+// neighbor returns the cell reached from the coord by making the move on the
+// given board.
+func (currentCoord Coord) neighbor(move SnakeDirectionType, board Board) Coord {
+	return board.geometry().neighbor(currentCoord, move)
+}
+
+// distanceOn returns the number of moves between both coords on the given
+// board, ignoring any obstacles.
+func (currentCoord Coord) distanceOn(other Coord, board Board) int {
+	return board.geometry().distance(currentCoord, other)
+}
+
+func mod(x int, n int) int {
+	return ((x % n) + n) % n
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWrappedGeometry(t *testing.T) {
+	board := Board{Height: 11, Width: 11, Ruleset: Ruleset{Name: RulesetWrapped}}
+
+	tests := []struct {
+		Name     string
+		From     Coord
+		Move     SnakeDirectionType
+		Expected Coord
+	}{
+		{Name: "Expect to enter right edge when leaving left edge", From: Coord{X: 0, Y: 5}, Move: SnakeDirection.LEFT, Expected: Coord{X: 10, Y: 5}},
+		{Name: "Expect to enter left edge when leaving right edge", From: Coord{X: 10, Y: 5}, Move: SnakeDirection.RIGHT, Expected: Coord{X: 0, Y: 5}},
+		{Name: "Expect to enter bottom edge when leaving top edge", From: Coord{X: 5, Y: 10}, Move: SnakeDirection.UP, Expected: Coord{X: 5, Y: 0}},
+		{Name: "Expect to enter top edge when leaving bottom edge", From: Coord{X: 5, Y: 0}, Move: SnakeDirection.DOWN, Expected: Coord{X: 5, Y: 10}},
+		{Name: "Expect normal move inside of board", From: Coord{X: 5, Y: 5}, Move: SnakeDirection.UP, Expected: Coord{X: 5, Y: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if next := tt.From.neighbor(tt.Move, board); !next.equals(tt.Expected) {
+				t.Errorf("Expected %v, got %v", tt.Expected, next)
+			}
+		})
+	}
+
+	if distance := (Coord{X: 0, Y: 0}).distanceOn(Coord{X: 10, Y: 10}, board); distance != 2 {
+		t.Errorf("Expected distance across the corner to be 2, got %d", distance)
+	}
+}
+
+func TestCollectNearestFoodOnWrappedBoard(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 50, Head: Coord{X: 1, Y: 5}, Body: []Coord{{X: 2, Y: 5}}, Length: 2}
+	board := Board{
+		Height:  11,
+		Width:   11,
+		Food:    []Coord{{X: 9, Y: 5}},
+		Snakes:  []Battlesnake{snake},
+		Ruleset: Ruleset{Name: RulesetWrapped},
+	}
+
+	move := CollectNearestFood{}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.LEFT {
+		t.Errorf("Expected to reach food across the edge (%s), got %s", SnakeDirection.LEFT, move)
+	}
+}
+
+func TestRulesWrapSnakesAroundEdges(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 50, Head: Coord{X: 0, Y: 5}, Body: []Coord{{X: 1, Y: 5}}, Length: 2}
+	board := Board{Height: 11, Width: 11, Snakes: []Battlesnake{snake}, Ruleset: Ruleset{Name: RulesetWrapped}}
+
+	next, eliminations := Rules{}.NextBoard(board, map[string]SnakeDirectionType{"1": SnakeDirection.LEFT})
+
+	if len(eliminations) != 0 {
+		t.Fatalf("Expected no eliminations on wrapped board, got %v", eliminations)
+	}
+	if !next.Snakes[0].Head.equals(Coord{X: 10, Y: 5}) {
+		t.Errorf("Expected snake to wrap to the right edge, got %v", next.Snakes[0].Head)
+	}
+}
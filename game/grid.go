@@ -0,0 +1,371 @@
+package game
+
+import "fmt"
+
+// outside is the cell of coords that are not on the board.
+const outside = -1
+
+// Grid is a compact representation of a Board for simulations. Cells are
+// numbered row by row and every cell knows how many snake segments are on it,
+// so that occupancy is checked in constant time instead of scanning all
+// bodies. Snake bodies are kept in ring buffers, so that a move only touches
+// the head and the tail of each snake. Moves are applied in place and can be
+// undone, which is cheaper than copying the board for every simulated turn.
+// Snakes are referred to by their index in the snakes of the board. MCTS plays
+// its rollouts on grids; Minimax still simulates with Rules.NextBoard.
+type Grid struct {
+	width  int
+	height int
+	// neighbors holds the cell reached by each of possibleMoves from every
+	// cell, or outside. It is shared by clones.
+	neighbors    [][4]int
+	occupied     []int32
+	food         []bool
+	hazards      []int32
+	hazardDamage int32
+	snakes       []gridSnake
+	// board holds everything that is not simulated, like the ruleset and
+	// the names of the snakes.
+	board Board
+}
+
+type gridSnake struct {
+	// body is a ring buffer of cells. The segments of the snake are the
+	// length cells ending at head.
+	body   []int
+	head   int
+	length int
+	health int32
+	alive  bool
+}
+
+// GridUndo holds what is needed to undo a move of a Grid.
+type GridUndo struct {
+	snakes []gridSnakeUndo
+	eaten  []int
+}
+
+type gridSnakeUndo struct {
+	head   int
+	length int
+	health int32
+	alive  bool
+	// replacedHead and replacedTail are the values of the ring buffer
+	// overwritten by the new head and by growing.
+	replacedHead int
+	replacedTail int
+}
+
+// NewGrid converts the board into a Grid.
+func NewGrid(board Board) *Grid {
+	cells := board.Width * board.Height
+	grid := &Grid{
+		width:        board.Width,
+		height:       board.Height,
+		neighbors:    make([][4]int, cells),
+		occupied:     make([]int32, cells),
+		food:         make([]bool, cells),
+		hazards:      make([]int32, cells),
+		hazardDamage: board.hazardDamage(),
+		snakes:       make([]gridSnake, len(board.Snakes)),
+		board:        board,
+	}
+	for cell := 0; cell < cells; cell++ {
+		for i, move := range possibleMoves {
+			grid.neighbors[cell][i] = grid.cell(grid.coord(cell).neighbor(move, board))
+		}
+	}
+	for _, food := range board.Food {
+		if cell := grid.cell(food); cell != outside {
+			grid.food[cell] = true
+		}
+	}
+	for _, hazard := range board.Hazards {
+		if cell := grid.cell(hazard); cell != outside {
+			grid.hazards[cell]++
+		}
+	}
+	for i, battlesnake := range board.Snakes {
+		segments := battlesnake.segments()
+		snake := gridSnake{
+			body:   make([]int, cells+len(segments)+2),
+			head:   len(segments) - 1,
+			length: len(segments),
+			health: battlesnake.Health,
+			alive:  true,
+		}
+		for j, segment := range segments {
+			snake.body[snake.head-j] = grid.cell(segment)
+		}
+		grid.snakes[i] = snake
+		grid.occupySnake(&grid.snakes[i], 1)
+	}
+	return grid
+}
+
+// Board converts the grid back into a Board. Snakes that were eliminated are
+// left out, food is listed row by row.
+func (grid *Grid) Board() Board {
+	board := grid.board
+	board.Food = nil
+	for cell, food := range grid.food {
+		if food {
+			board.Food = append(board.Food, grid.coord(cell))
+		}
+	}
+	board.Snakes = nil
+	for i, snake := range grid.snakes {
+		if !snake.alive {
+			continue
+		}
+		battlesnake := grid.board.Snakes[i]
+		battlesnake.Body = make([]Coord, snake.length)
+		for j := range battlesnake.Body {
+			battlesnake.Body[j] = grid.coord(snake.segment(j))
+		}
+		battlesnake.Head = battlesnake.Body[0]
+		battlesnake.Health = snake.health
+		battlesnake.Length = int32(snake.length)
+		board.Snakes = append(board.Snakes, battlesnake)
+	}
+	return board
+}
+
+// Clone returns an independent copy of the grid.
+func (grid *Grid) Clone() *Grid {
+	clone := *grid
+	clone.occupied = append([]int32{}, grid.occupied...)
+	clone.food = append([]bool{}, grid.food...)
+	clone.snakes = make([]gridSnake, len(grid.snakes))
+	for i, snake := range grid.snakes {
+		snake.body = append([]int{}, snake.body...)
+		clone.snakes[i] = snake
+	}
+	return &clone
+}
+
+func (grid *Grid) cell(coord Coord) int {
+	if coord.X < 0 || coord.Y < 0 || coord.X >= grid.width || coord.Y >= grid.height {
+		return outside
+	}
+	return coord.X + coord.Y*grid.width
+}
+
+func (grid *Grid) coord(cell int) Coord {
+	return Coord{cell % grid.width, cell / grid.width}
+}
+
+// segment returns the cell of the snake's segment with the given index, the
+// head being 0.
+func (snake *gridSnake) segment(index int) int {
+	return snake.body[mod(snake.head-index, len(snake.body))]
+}
+
+func (snake *gridSnake) tailIndex() int {
+	return mod(snake.head-snake.length+1, len(snake.body))
+}
+
+func (grid *Grid) occupy(cell int, count int) {
+	if cell != outside {
+		grid.occupied[cell] += int32(count)
+	}
+}
+
+func (grid *Grid) occupySnake(snake *gridSnake, count int) {
+	for i := 0; i < snake.length; i++ {
+		grid.occupy(snake.segment(i), count)
+	}
+}
+
+// IsOccupied tells whether any snake segment is on the coord.
+func (grid *Grid) IsOccupied(coord Coord) bool {
+	cell := grid.cell(coord)
+	return cell != outside && grid.occupied[cell] > 0
+}
+
+// IsAlive tells whether the snake has not been eliminated.
+func (grid *Grid) IsAlive(index int) bool {
+	return grid.snakes[index].alive
+}
+
+// IsSafe tells whether the snake can move onto the coord, just like
+// Coord.isSafe.
+func (grid *Grid) IsSafe(index int, coord Coord) bool {
+	return grid.isSafe(index, grid.cell(coord))
+}
+
+func (grid *Grid) isSafe(index int, cell int) bool {
+	snake := &grid.snakes[index]
+	if cell != outside && cell == snake.body[snake.tailIndex()] && snake.health < maxHealth {
+		return true
+	}
+	return cell != outside && grid.occupied[cell] == 0 && !grid.isDeadlyHazard(snake, cell)
+}
+
+// candidateMoves returns the safe moves of the snake with the given index, or
+// up if there are none, just like candidateMoves.
+func (grid *Grid) candidateMoves(index int) []SnakeDirectionType {
+	var moves []SnakeDirectionType
+	head := grid.snakes[index].segment(0)
+	for i, move := range possibleMoves {
+		if grid.isSafe(index, grid.neighbors[head][i]) {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		return []SnakeDirectionType{SnakeDirection.UP}
+	}
+	return moves
+}
+
+func (grid *Grid) isDeadlyHazard(snake *gridSnake, cell int) bool {
+	if grid.hazards[cell] == 0 {
+		return false
+	}
+	cost := int32(1)
+	if !grid.food[cell] {
+		cost += grid.hazards[cell] * grid.hazardDamage
+	}
+	return snake.health <= cost
+}
+
+// Move applies one move per snake, by index, like Rules.NextBoard without
+// spawning food. Eliminated snakes stay in the grid, but are no longer alive
+// and their moves are ignored. The grid is left unchanged if a move of a
+// living snake is unknown.
+func (grid *Grid) Move(moves []SnakeDirectionType) (GridUndo, error) {
+	if len(moves) != len(grid.snakes) {
+		return GridUndo{}, fmt.Errorf("got %d moves for %d snakes", len(moves), len(grid.snakes))
+	}
+	indices := make([]int, len(moves))
+	for i, move := range moves {
+		index, ok := moveIndex(move)
+		if !ok && grid.snakes[i].alive {
+			return GridUndo{}, fmt.Errorf("unknown move %q of snake %d", move, i)
+		}
+		indices[i] = index
+	}
+
+	undo := GridUndo{snakes: make([]gridSnakeUndo, len(grid.snakes))}
+	for i := range grid.snakes {
+		snake := &grid.snakes[i]
+		undo.snakes[i] = gridSnakeUndo{head: snake.head, length: snake.length, health: snake.health, alive: snake.alive}
+		if !snake.alive {
+			continue
+		}
+		next := grid.neighbors[snake.segment(0)][indices[i]]
+		grid.occupy(snake.body[snake.tailIndex()], -1)
+		snake.head = (snake.head + 1) % len(snake.body)
+		undo.snakes[i].replacedHead = snake.body[snake.head]
+		snake.body[snake.head] = next
+		grid.occupy(next, 1)
+
+		snake.health--
+		if next != outside && !grid.food[next] {
+			snake.health -= grid.hazards[next] * grid.hazardDamage
+		}
+	}
+
+	for i := range grid.snakes {
+		snake := &grid.snakes[i]
+		head := snake.segment(0)
+		if !snake.alive || head == outside || !grid.food[head] {
+			continue
+		}
+		snake.health = maxHealth
+		snake.length++
+		tail := snake.tailIndex()
+		undo.snakes[i].replacedTail = snake.body[tail]
+		snake.body[tail] = snake.body[(tail+1)%len(snake.body)]
+		grid.occupy(snake.body[tail], 1)
+		undo.eaten = append(undo.eaten, head)
+	}
+	for _, cell := range undo.eaten {
+		grid.food[cell] = false
+	}
+
+	for i := range grid.snakes {
+		snake := &grid.snakes[i]
+		if snake.alive && (snake.health <= 0 || snake.segment(0) == outside) {
+			grid.eliminate(snake)
+		}
+	}
+
+	// Collisions are resolved simultaneously, like in eliminateSnakes.
+	var collided []int
+	for i := range grid.snakes {
+		if grid.snakes[i].alive && grid.collides(i) {
+			collided = append(collided, i)
+		}
+	}
+	for _, i := range collided {
+		grid.eliminate(&grid.snakes[i])
+	}
+
+	return undo, nil
+}
+
+// collides tells whether the head of the snake with the given index hits a
+// body or loses a head-to-head collision.
+func (grid *Grid) collides(index int) bool {
+	snake := &grid.snakes[index]
+	head := snake.segment(0)
+	heads := 0
+	for i := range grid.snakes {
+		other := &grid.snakes[i]
+		if !other.alive || other.segment(0) != head {
+			continue
+		}
+		heads++
+		if i != index && snake.length <= other.length {
+			return true
+		}
+	}
+	return int(grid.occupied[head]) > heads
+}
+
+func (grid *Grid) eliminate(snake *gridSnake) {
+	grid.occupySnake(snake, -1)
+	snake.alive = false
+}
+
+// Undo reverts the move that returned the given undo. Moves have to be undone
+// in reverse order.
+func (grid *Grid) Undo(undo GridUndo) {
+	for _, cell := range undo.eaten {
+		grid.food[cell] = true
+	}
+	for i := range grid.snakes {
+		snake := &grid.snakes[i]
+		state := undo.snakes[i]
+		if !state.alive {
+			continue
+		}
+		if !snake.alive {
+			grid.occupySnake(snake, 1)
+			snake.alive = true
+		}
+		if snake.length > state.length {
+			tail := snake.tailIndex()
+			grid.occupy(snake.body[tail], -1)
+			snake.body[tail] = state.replacedTail
+		}
+		grid.occupy(snake.body[snake.head], -1)
+		snake.body[snake.head] = state.replacedHead
+		snake.head = state.head
+		snake.length = state.length
+		snake.health = state.health
+		grid.occupy(snake.body[snake.tailIndex()], 1)
+	}
+}
+
+// moveIndex returns the index of the move in possibleMoves, or false if the
+// move is unknown.
+func moveIndex(move SnakeDirectionType) (int, bool) {
+	for i, possible := range possibleMoves {
+		if move == possible {
+			return i, true
+		}
+	}
+	return 0, false
+}
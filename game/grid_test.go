@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func createGridTestBoard(ruleset string, rnd *rand.Rand) Board {
+	board, _ := NewStandardBoard(11, 11, []string{"1", "2", "3", "4"}, rnd)
+	board.Ruleset = Ruleset{Name: ruleset}
+	board.Hazards = []Coord{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 1}, {X: 10, Y: 5}}
+	return board
+}
+
+// describeBoard returns the parts of the board that a Grid simulates, food
+// sorted like Grid.Board does.
+func describeBoard(board Board) string {
+	food := append([]Coord{}, board.Food...)
+	sort.Slice(food, func(i, j int) bool {
+		return food[i].X+food[i].Y*board.Width < food[j].X+food[j].Y*board.Width
+	})
+	description := fmt.Sprintf("food %v", food)
+	for _, snake := range board.Snakes {
+		description += fmt.Sprintf(", snake %s health %d length %d body %v", snake.ID, snake.Health, snake.Length, snake.segments())
+	}
+	return description
+}
+
+func TestGridConversion(t *testing.T) {
+	board := createGridTestBoard(RulesetStandard, rand.New(rand.NewSource(1)))
+
+	converted := NewGrid(board).Board()
+
+	if describeBoard(converted) != describeBoard(board) {
+		t.Errorf("Expected %s, got %s", describeBoard(board), describeBoard(converted))
+	}
+	if converted.Snakes[0].Name != board.Snakes[0].Name || converted.Ruleset.Name != board.Ruleset.Name {
+		t.Errorf("Expected names and ruleset to be kept")
+	}
+}
+
+func TestGridMoveFollowsRules(t *testing.T) {
+	for _, ruleset := range []string{RulesetStandard, RulesetWrapped} {
+		for seed := int64(1); seed <= 20; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", ruleset, seed), func(t *testing.T) {
+				rnd := rand.New(rand.NewSource(seed))
+				board := createGridTestBoard(ruleset, rnd)
+				grid := NewGrid(board)
+				start := describeBoard(board)
+
+				var undos []GridUndo
+				for turn := 0; turn < 100 && len(board.Snakes) > 0; turn++ {
+					moves := map[string]SnakeDirectionType{}
+					gridMoves := make([]SnakeDirectionType, len(grid.snakes))
+					for i, original := range grid.board.Snakes {
+						// Mostly safe moves, so that snakes live long
+						// enough to eat and to meet each other.
+						candidates := possibleMoves
+						if snake, alive := board.snake(original.ID); alive && rnd.Intn(10) > 0 {
+							candidates = candidateMoves(snake, board)
+						}
+						move := candidates[rnd.Intn(len(candidates))]
+						moves[original.ID] = move
+						gridMoves[i] = move
+					}
+					board, _ = Rules{}.NextBoard(board, moves)
+					undo, err := grid.Move(gridMoves)
+					if err != nil {
+						t.Fatal(err)
+					}
+					undos = append(undos, undo)
+
+					if describeBoard(grid.Board()) != describeBoard(board) {
+						t.Fatalf("Expected %s on turn %d, got %s", describeBoard(board), turn+1, describeBoard(grid.Board()))
+					}
+				}
+
+				for i := len(undos) - 1; i >= 0; i-- {
+					grid.Undo(undos[i])
+				}
+				if describeBoard(grid.Board()) != start {
+					t.Errorf("Expected %s after undo, got %s", start, describeBoard(grid.Board()))
+				}
+			})
+		}
+	}
+}
+
+func TestGridIsSafe(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	board := createGridTestBoard(RulesetStandard, rnd)
+	board.Snakes[0].Health = 20
+	for turn := 0; turn < 10; turn++ {
+		board, _ = Rules{}.NextBoard(board, map[string]SnakeDirectionType{})
+	}
+	grid := NewGrid(board)
+
+	for i, snake := range board.Snakes {
+		for x := -1; x <= board.Width; x++ {
+			for y := -1; y <= board.Height; y++ {
+				coord := Coord{x, y}
+				if grid.IsSafe(i, coord) != coord.isSafe(snake, board) {
+					t.Errorf("Expected grid to agree on safety of %v for snake %s", coord, snake.ID)
+				}
+				if grid.IsOccupied(coord) != coord.isInSnakes(board) {
+					t.Errorf("Expected grid to agree on occupancy of %v", coord)
+				}
+			}
+		}
+		if !reflect.DeepEqual(grid.candidateMoves(i), candidateMoves(snake, board)) {
+			t.Errorf("Expected grid to agree on candidate moves of snake %s", snake.ID)
+		}
+	}
+}
+
+func TestGridRejectsUnknownMoves(t *testing.T) {
+	board := createGridTestBoard(RulesetStandard, rand.New(rand.NewSource(1)))
+	grid := NewGrid(board)
+	start := describeBoard(grid.Board())
+
+	tests := []struct {
+		Name  string
+		Moves []SnakeDirectionType
+	}{
+		{"Expect unknown move to be rejected", []SnakeDirectionType{SnakeDirection.UP, "north", SnakeDirection.UP, SnakeDirection.UP}},
+		{"Expect missing move to be rejected", []SnakeDirectionType{SnakeDirection.UP, "", SnakeDirection.UP, SnakeDirection.UP}},
+		{"Expect moves of too few snakes to be rejected", []SnakeDirectionType{SnakeDirection.UP}},
+	}
+
+	for _, tt := range tests {
+		if _, err := grid.Move(tt.Moves); err == nil {
+			t.Errorf("%s", tt.Name)
+		}
+		if describeBoard(grid.Board()) != start {
+			t.Errorf("%s: expected grid to be unchanged, got %s", tt.Name, describeBoard(grid.Board()))
+		}
+	}
+}
+
+func TestGridCountsStackedSegments(t *testing.T) {
+	// 256 segments overflowed the occupancy of a cell when it was a byte.
+	body := make([]Coord, 256)
+	for i := range body {
+		body[i] = Coord{X: 5, Y: 5}
+	}
+	board := Board{Width: 11, Height: 11, Snakes: []Battlesnake{{ID: "1", Health: 100, Head: body[0], Body: body}}}
+	grid := NewGrid(board)
+
+	if !grid.IsOccupied(Coord{X: 5, Y: 5}) {
+		t.Errorf("Expected %d stacked segments to occupy their cell", len(body))
+	}
+	if _, err := grid.Move([]SnakeDirectionType{SnakeDirection.UP}); err != nil {
+		t.Fatal(err)
+	}
+	if !grid.IsOccupied(Coord{X: 5, Y: 5}) || !grid.IsOccupied(Coord{X: 5, Y: 6}) {
+		t.Errorf("Expected the tail to stay stacked behind the head")
+	}
+}
+
+func BenchmarkIsSafe(b *testing.B) {
+	board := createGridTestBoard(RulesetStandard, rand.New(rand.NewSource(1)))
+	snake := board.Snakes[0]
+
+	b.Run("Coord", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for cell := 0; cell < board.Width*board.Height; cell++ {
+				Coord{cell % board.Width, cell / board.Width}.isSafe(snake, board)
+			}
+		}
+	})
+	b.Run("Grid", func(b *testing.B) {
+		grid := NewGrid(board)
+		for n := 0; n < b.N; n++ {
+			for cell := 0; cell < board.Width*board.Height; cell++ {
+				grid.isSafe(0, cell)
+			}
+		}
+	})
+}
+
+func BenchmarkMove(b *testing.B) {
+	board := createGridTestBoard(RulesetStandard, rand.New(rand.NewSource(1)))
+	moves := map[string]SnakeDirectionType{}
+	var gridMoves []SnakeDirectionType
+	for _, snake := range board.Snakes {
+		move := snake.currentDirection(board)
+		moves[snake.ID] = move
+		gridMoves = append(gridMoves, move)
+	}
+
+	b.Run("NextBoard", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			Rules{}.NextBoard(board, moves)
+		}
+	})
+	b.Run("GridMoveAndUndo", func(b *testing.B) {
+		grid := NewGrid(board)
+		for n := 0; n < b.N; n++ {
+			undo, _ := grid.Move(gridMoves)
+			grid.Undo(undo)
+		}
+	})
+	b.Run("GridCloneAndMove", func(b *testing.B) {
+		grid := NewGrid(board)
+		for n := 0; n < b.N; n++ {
+			grid.Clone().Move(gridMoves)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package game
+
+import "context"
+
+// defaultHazardDamage is the damage hazards deal in royale games. It is
+// assumed for boards with hazards whose ruleset is unknown.
+const defaultHazardDamage int32 = 14
+
+// hazardDamage returns the health a snake loses in addition to the normal
+// decay for each hazard on the cell its head is on.
+func (board Board) hazardDamage() int32 {
+	damage := int32(board.Ruleset.Settings.HazardDamagePerTurn)
+	if damage == 0 && len(board.Ruleset.Name) == 0 {
+		return defaultHazardDamage
+	}
+	return damage
+}
+
+// hazardCount returns how often the coord is listed as hazard. Hazards may be
+// stacked, in which case their damage adds up.
+func (currentCoord Coord) hazardCount(board Board) int32 {
+	var count int32
+	for _, hazard := range board.Hazards {
+		if currentCoord.equals(hazard) {
+			count++
+		}
+	}
+	return count
+}
+
+func (currentCoord Coord) isHazard(board Board) bool {
+	return currentCoord.hazardCount(board) > 0
+}
+
+// healthCost returns the health a snake loses when its head moves onto the
+// coord. Hazards deal no damage to snakes that eat food on them.
+func (currentCoord Coord) healthCost(board Board) int32 {
+	if currentCoord.isIn(board.Food) {
+		return 1
+	}
+	return 1 + currentCoord.hazardCount(board)*board.hazardDamage()
+}
+
+// isDeadlyHazard tells whether moving onto the coord would starve the snake
+// because of hazard damage.
+func (currentCoord Coord) isDeadlyHazard(battlesnake Battlesnake, board Board) bool {
+	return currentCoord.isHazard(board) && battlesnake.Health <= currentCoord.healthCost(board)
+}
+
+// LeaveHazard moves the snake out of hazards by the route that costs the least
+// health.
+type LeaveHazard struct{}
+
+func (LeaveHazard) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	var targets []Coord
+	for x := 0; x < board.Width; x++ {
+		for y := 0; y < board.Height; y++ {
+			coord := Coord{x, y}
+			if !coord.isHazard(board) {
+				targets = append(targets, coord)
+			}
+		}
+	}
+	if path, ok := findPath(snake, board, targets); ok {
+		return snake.Head.directionTo(path[0], board)
+	}
+	traceFrom(ctx).fallback("LeaveHazard", "", "no path out of hazards")
+	return getSafeMove(ctx, snake, board)
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHazardsAreOnlyUnsafeWhenDeadly(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Health   int32
+		Food     []Coord
+		Expected bool
+	}{
+		{
+			Name:     "Expect hazard to be safe with enough health",
+			Health:   50,
+			Expected: true,
+		},
+		{
+			Name:     "Expect hazard to be unsafe with low health",
+			Health:   15,
+			Expected: false,
+		},
+		{
+			Name:     "Expect hazard with food to be safe with low health",
+			Health:   2,
+			Food:     []Coord{{X: 3, Y: 2}},
+			Expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			snake := Battlesnake{ID: "1", Health: tt.Health, Head: Coord{X: 2, Y: 2}, Body: []Coord{{X: 1, Y: 2}}, Length: 2}
+			board := Board{Height: 5, Width: 5, Food: tt.Food, Hazards: []Coord{{X: 3, Y: 2}}, Snakes: []Battlesnake{snake}}
+
+			if safe := (Coord{X: 3, Y: 2}).isSafe(snake, board); safe != tt.Expected {
+				t.Errorf("Expected hazard safety to be %t, got %t", tt.Expected, safe)
+			}
+		})
+	}
+}
+
+func TestCollectNearestFoodWithHazards(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Health   int32
+		Food     []Coord
+		Hazards  []Coord
+		Expected SnakeDirectionType
+	}{
+		{
+			Name:     "Expect to walk around hazards when it is cheaper",
+			Health:   50,
+			Food:     []Coord{{X: 4, Y: 2}},
+			Hazards:  []Coord{{X: 3, Y: 2}, {X: 3, Y: 1}},
+			Expected: SnakeDirection.UP,
+		},
+		{
+			Name:     "Expect to eat food in hazard",
+			Health:   10,
+			Food:     []Coord{{X: 3, Y: 2}},
+			Hazards:  []Coord{{X: 3, Y: 2}},
+			Expected: SnakeDirection.RIGHT,
+		},
+		{
+			Name:     "Expect to cross hazard when food is worth it",
+			Health:   30,
+			Food:     []Coord{{X: 4, Y: 2}},
+			Hazards:  []Coord{{X: 3, Y: 0}, {X: 3, Y: 1}, {X: 3, Y: 2}, {X: 3, Y: 3}, {X: 3, Y: 4}},
+			Expected: SnakeDirection.RIGHT,
+		},
+	}
+
+	action := CollectNearestFood{}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			snake := Battlesnake{ID: "1", Health: tt.Health, Head: Coord{X: 2, Y: 2}, Body: []Coord{{X: 1, Y: 2}}, Length: 2}
+			board := Board{Height: 5, Width: 5, Food: tt.Food, Hazards: tt.Hazards, Snakes: []Battlesnake{snake}}
+
+			move := action.Execute(context.Background(), snake, board)
+			if move != tt.Expected {
+				t.Errorf("Snake does not move in direction of food (%s), %s instead", tt.Expected, move)
+			}
+		})
+	}
+}
+
+func TestLeaveHazard(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 60, Head: Coord{X: 1, Y: 2}, Body: []Coord{{X: 1, Y: 1}, {X: 1, Y: 0}}, Length: 3}
+	board := Board{
+		Height: 5,
+		Width:  5,
+		// The hazard right of the snake is stacked, so leaving upwards is cheaper.
+		Hazards: []Coord{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 0, Y: 3}, {X: 0, Y: 4}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}, {X: 1, Y: 4}, {X: 2, Y: 2}, {X: 2, Y: 2}},
+		Snakes:  []Battlesnake{snake},
+	}
+
+	move := LeaveHazard{}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.UP {
+		t.Errorf("Snake does not leave hazard by cheapest route (%s), %s instead", SnakeDirection.UP, move)
+	}
+}
+
+func TestRulesDamageSnakesInHazards(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 50, Head: Coord{X: 2, Y: 2}, Body: []Coord{{X: 1, Y: 2}}, Length: 2}
+	board := Board{
+		Height:  5,
+		Width:   5,
+		Hazards: []Coord{{X: 3, Y: 2}},
+		Snakes:  []Battlesnake{snake},
+		Ruleset: Ruleset{Name: RulesetRoyale, Settings: RulesetSettings{HazardDamagePerTurn: 10}},
+	}
+
+	next, _ := Rules{}.NextBoard(board, map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT})
+
+	if next.Snakes[0].Health != 39 {
+		t.Errorf("Expected health of 39 after hazard damage, got %d", next.Snakes[0].Health)
+	}
+}
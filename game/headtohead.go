@@ -0,0 +1,105 @@
+package game
+
+import "context"
+
+// headToHeadRisk describes what may happen on a cell next turn because an
+// opponent's head can move there as well.
+type headToHeadRisk int
+
+const (
+	noHeadToHead headToHeadRisk = iota
+	// winningHeadToHead means only shorter opponents can reach the cell, so
+	// meeting them there eliminates them.
+	winningHeadToHead
+	// losingHeadToHead means an opponent of at least our length can reach the
+	// cell, so meeting it there eliminates us.
+	losingHeadToHead
+)
+
+// headToHeadRisks returns the risk for every cell the heads of the snake's
+// opponents can move to next turn.
+func headToHeadRisks(battlesnake Battlesnake, board Board) map[Coord]headToHeadRisk {
+	risks := map[Coord]headToHeadRisk{}
+	for _, opponent := range board.Snakes {
+		if opponent.ID == battlesnake.ID {
+			continue
+		}
+		risk := winningHeadToHead
+		if opponent.Length >= battlesnake.Length {
+			risk = losingHeadToHead
+		}
+		for _, move := range possibleMoves {
+			coord := opponent.Head.neighbor(move, board)
+			if risk > risks[coord] {
+				risks[coord] = risk
+			}
+		}
+	}
+	return risks
+}
+
+// ContestHeads seeks cells where the snake could win a head-to-head collision
+// and avoids cells where it could lose one. Contests are only sought if the
+// area behind the cell can hold the snake. If neither applies, the move of
+// Action is kept. Otherwise, or without an Action, the least risky move
+// leading into the largest area is made.
+type ContestHeads struct {
+	Action Action
+}
+
+func (contest ContestHeads) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	risks := headToHeadRisks(snake, board)
+	areas := reachableAreaPerMove(snake, board)
+	for move, area := range areas {
+		// Winning a contest doesn't help if the snake is trapped after it.
+		coord := snake.Head.neighbor(move, board)
+		if risks[coord] == winningHeadToHead && area < int(snake.Length) {
+			risks[coord] = noHeadToHead
+		}
+	}
+
+	bestMove := SnakeDirectionType("")
+	for _, move := range possibleMoves {
+		area, ok := areas[move]
+		if !ok {
+			continue
+		}
+		risk := risks[snake.Head.neighbor(move, board)]
+		bestRisk := risks[snake.Head.neighbor(bestMove, board)]
+		if bestMove == "" || preferHeadToHead(risk, bestRisk) || (risk == bestRisk && area > areas[bestMove]) {
+			bestMove = move
+		}
+	}
+	if bestMove == "" {
+		return getSafeMove(ctx, snake, board)
+	}
+	trace := traceFrom(ctx)
+	if risks[snake.Head.neighbor(bestMove, board)] == winningHeadToHead {
+		trace.choose("ContestHeads", bestMove, "winning head-to-head")
+		return bestMove
+	}
+	if contest.Action == nil {
+		return bestMove
+	}
+
+	move := contest.Action.Execute(ctx, snake, board)
+	_, ok := areas[move]
+	if ok && risks[snake.Head.neighbor(move, board)] != losingHeadToHead {
+		return move
+	}
+	if !ok {
+		trace.reject("ContestHeads", move, "unsafe")
+	} else {
+		trace.reject("ContestHeads", move, "losing head-to-head")
+	}
+	trace.choose("ContestHeads", bestMove, "least risky move")
+	return bestMove
+}
+
+// preferHeadToHead tells whether a cell with the given risk is better than one
+// with the other risk: winning is better than nothing, which is better than
+// losing.
+func preferHeadToHead(risk headToHeadRisk, other headToHeadRisk) bool {
+	rank := map[headToHeadRisk]int{winningHeadToHead: 2, noHeadToHead: 1, losingHeadToHead: 0}
+	return rank[risk] > rank[other]
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContestHeads(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Opponent []Coord
+		Action   Action
+		Expected []SnakeDirectionType
+	}{
+		{
+			Name:     "Expect to avoid head-to-head with longer opponent",
+			Opponent: []Coord{{X: 7, Y: 5}, {X: 8, Y: 5}, {X: 9, Y: 5}, {X: 10, Y: 5}},
+			Action:   fixedMove(SnakeDirection.RIGHT),
+			Expected: []SnakeDirectionType{SnakeDirection.UP, SnakeDirection.LEFT},
+		},
+		{
+			Name:     "Expect to avoid head-to-head with opponent of same length",
+			Opponent: []Coord{{X: 7, Y: 5}, {X: 8, Y: 5}, {X: 9, Y: 5}},
+			Action:   fixedMove(SnakeDirection.RIGHT),
+			Expected: []SnakeDirectionType{SnakeDirection.UP, SnakeDirection.LEFT},
+		},
+		{
+			Name:     "Expect to seek head-to-head with shorter opponent",
+			Opponent: []Coord{{X: 7, Y: 5}, {X: 8, Y: 5}},
+			Action:   fixedMove(SnakeDirection.UP),
+			Expected: []SnakeDirectionType{SnakeDirection.RIGHT},
+		},
+		{
+			Name:     "Expect to keep move of action without opponents nearby",
+			Opponent: []Coord{{X: 9, Y: 9}, {X: 9, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 9}},
+			Action:   fixedMove(SnakeDirection.LEFT),
+			Expected: []SnakeDirectionType{SnakeDirection.LEFT},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 5, Y: 5}, Body: []Coord{{X: 5, Y: 4}, {X: 5, Y: 3}}, Length: 3}
+			opponent := Battlesnake{ID: "2", Health: 90, Head: tt.Opponent[0], Body: tt.Opponent[1:], Length: int32(len(tt.Opponent))}
+			board := Board{Height: 11, Width: 11, Snakes: []Battlesnake{snake, opponent}}
+
+			move := ContestHeads{tt.Action}.Execute(context.Background(), snake, board)
+
+			for _, expected := range tt.Expected {
+				if move == expected {
+					return
+				}
+			}
+			t.Errorf("Expected one of %v, got %s", tt.Expected, move)
+		})
+	}
+}
+
+func TestContestHeadsAvoidsPockets(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		+---------------+
+		| . . C < . . . |
+		| . . . ^ < < < |
+		| > > A . B < ^ |
+		| . . . > > > ^ |
+		| . . > ^ . . . |
+		+---------------+
+	`)
+
+	move := ContestHeads{fixedMove(SnakeDirection.DOWN)}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.DOWN {
+		t.Errorf("Expected to leave head-to-head in a pocket alone and keep %s, got %s\n%s", SnakeDirection.DOWN, move, RenderBoard(board, snake.ID))
+	}
+}
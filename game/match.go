@@ -0,0 +1,188 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+// Player is a participant of a Match.
+type Player struct {
+	Name     string
+	Strategy Strategy
+}
+
+// Match plays a full game between several strategies without any server
+// involved.
+type Match struct {
+	Width    int
+	Height   int
+	Players  []Player
+	Seed     int64
+	MaxTurns int
+	Rules    Rules
+	// Ruleset is made available to the strategies on the board. Its name
+	// decides about the board's geometry.
+	Ruleset Ruleset
+	// MoveTimeout is the time each snake has to decide on a move. There is
+	// no limit if it is zero.
+	MoveTimeout time.Duration
+	// Logger receives the log lines of the strategies, logging.Default if
+	// it is nil.
+	Logger *logging.Logger
+}
+
+// MatchElimination is an elimination together with the turn it happened in.
+type MatchElimination struct {
+	Elimination
+	Turn int
+}
+
+// MatchResult describes how a match ended.
+type MatchResult struct {
+	// Winner is the ID of the last snake standing. It is empty if the
+	// match ended in a draw or ran out of turns.
+	Winner       string
+	Turns        int
+	Eliminations []MatchElimination
+	// Names maps snake IDs to the name of the player controlling them.
+	Names map[string]string
+}
+
+// Play runs the match until at most one snake is left (none for a single
+// player match) or MaxTurns is reached. It fails if the players don't fit on
+// the board.
+func (match Match) Play() (MatchResult, error) {
+	rnd := rand.New(rand.NewSource(match.Seed))
+	rules := match.Rules
+	rules.Rand = rnd
+
+	result := MatchResult{Names: map[string]string{}}
+	var ids []string
+	snakes := map[string]*StrategicBattlesnake{}
+	for i, player := range match.Players {
+		id := fmt.Sprintf("snake-%d", i+1)
+		ids = append(ids, id)
+		result.Names[id] = player.Name
+		snakes[id] = &StrategicBattlesnake{Strategy: player.Strategy}
+	}
+
+	board, err := NewStandardBoard(match.Width, match.Height, ids, rnd)
+	if err != nil {
+		return result, err
+	}
+	board.Ruleset = match.Ruleset
+	for !match.isOver(board, result.Turns) {
+		moves := map[string]SnakeDirectionType{}
+		for _, snake := range board.Snakes {
+			strategic := snakes[snake.ID]
+			strategic.Snake = snake
+			moves[snake.ID] = match.nextMove(strategic, board)
+		}
+
+		var eliminations []Elimination
+		board, eliminations = rules.NextBoard(board, moves)
+		result.Turns++
+		for _, elimination := range eliminations {
+			result.Eliminations = append(result.Eliminations, MatchElimination{elimination, result.Turns})
+		}
+	}
+
+	if len(board.Snakes) == 1 {
+		result.Winner = board.Snakes[0].ID
+	}
+	return result, nil
+}
+
+func (match Match) nextMove(strategic *StrategicBattlesnake, board Board) SnakeDirectionType {
+	ctx := context.Background()
+	if match.Logger != nil {
+		ctx = logging.NewContext(ctx, match.Logger)
+	}
+	if match.MoveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, match.MoveTimeout)
+		defer cancel()
+	}
+	move, _ := strategic.NextMove(ctx, board)
+	return move
+}
+
+func (match Match) isOver(board Board, turn int) bool {
+	if match.MaxTurns > 0 && turn >= match.MaxTurns {
+		return true
+	}
+	if len(match.Players) == 1 {
+		return len(board.Snakes) == 0
+	}
+	return len(board.Snakes) <= 1
+}
+
+// NewStandardBoard creates the starting board of a standard game: every snake
+// starts with three segments stacked on one of the fixed start positions and
+// gets a piece of food diagonally next to it. One more piece of food is put in
+// the center. There are start positions for at most 8 snakes, on boards of at
+// least MinimumBoardSize cells in both directions.
+func NewStandardBoard(width int, height int, snakeIDs []string, rnd *rand.Rand) (Board, error) {
+	if width < MinimumBoardSize || height < MinimumBoardSize {
+		return Board{}, fmt.Errorf("boards must be at least %dx%d, got %dx%d", MinimumBoardSize, MinimumBoardSize, width, height)
+	}
+	board := Board{Width: width, Height: height, Food: []Coord{}, Snakes: []Battlesnake{}}
+
+	startCoords := startPositions(width, height)
+	corners, edges := startCoords[:4], startCoords[4:]
+	rnd.Shuffle(len(corners), func(i, j int) { corners[i], corners[j] = corners[j], corners[i] })
+	rnd.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	if len(snakeIDs) > len(startCoords) {
+		return Board{}, fmt.Errorf("at most %d snakes are supported, got %d", len(startCoords), len(snakeIDs))
+	}
+
+	for i, id := range snakeIDs {
+		head := startCoords[i]
+		board.Snakes = append(board.Snakes, Battlesnake{
+			ID:     id,
+			Name:   id,
+			Health: maxHealth,
+			Head:   head,
+			Body:   []Coord{head, head, head},
+			Length: 3,
+		})
+	}
+
+	center := Coord{(width - 1) / 2, (height - 1) / 2}
+	for _, snake := range board.Snakes {
+		var candidates []Coord
+		for _, offset := range []Coord{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+			coord := Coord{snake.Head.X + offset.X, snake.Head.Y + offset.Y}
+			if coord.isOutsideOfArea(board) || coord.equals(center) || coord.isInSnakes(board) || coord.isIn(board.Food) {
+				continue
+			}
+			candidates = append(candidates, coord)
+		}
+		if len(candidates) > 0 {
+			board.Food = append(board.Food, candidates[rnd.Intn(len(candidates))])
+		}
+	}
+	if !center.isInSnakes(board) {
+		board.Food = append(board.Food, center)
+	}
+
+	return board, nil
+}
+
+// MinimumBoardSize is the smallest width and height of a board on which the
+// start positions of NewStandardBoard are all different.
+const MinimumBoardSize = 5
+
+// startPositions returns the start positions of a board, corners first.
+func startPositions(width int, height int) []Coord {
+	minX, midX, maxX := 1, (width-1)/2, width-2
+	minY, midY, maxY := 1, (height-1)/2, height-2
+	return []Coord{
+		{minX, minY}, {minX, maxY}, {maxX, minY}, {maxX, maxY},
+		{minX, midY}, {midX, minY}, {midX, maxY}, {maxX, midY},
+	}
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewStandardBoard(t *testing.T) {
+	board, err := NewStandardBoard(11, 11, []string{"1", "2", "3"}, rand.New(rand.NewSource(1)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Snakes) != 3 {
+		t.Fatalf("Expected 3 snakes, got %d", len(board.Snakes))
+	}
+	for _, snake := range board.Snakes {
+		if snake.Length != 3 || snake.Health != maxHealth {
+			t.Errorf("Expected snake %s to start with length 3 and full health, got %d and %d", snake.ID, snake.Length, snake.Health)
+		}
+		if snake.Head.isOutsideOfArea(board) {
+			t.Errorf("Expected snake %s to start on the board, got %v", snake.ID, snake.Head)
+		}
+	}
+	if len(board.Food) != 4 {
+		t.Errorf("Expected one food per snake and one in the center, got %v", board.Food)
+	}
+}
+
+func TestNewStandardBoardWithTooManySnakes(t *testing.T) {
+	ids := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}
+
+	if _, err := NewStandardBoard(11, 11, ids, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("Expected an error for %d snakes", len(ids))
+	}
+	if _, err := NewStandardBoard(11, 11, ids[:8], rand.New(rand.NewSource(1))); err != nil {
+		t.Errorf("Expected 8 snakes to fit, got %v", err)
+	}
+}
+
+func TestNewStandardBoardSizes(t *testing.T) {
+	tests := []struct {
+		Width  int
+		Height int
+		Valid  bool
+	}{
+		{11, 11, true},
+		{MinimumBoardSize, MinimumBoardSize, true},
+		{MinimumBoardSize - 1, 11, false},
+		{11, 0, false},
+		{-3, -3, false},
+	}
+	ids := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+
+	for _, tt := range tests {
+		board, err := NewStandardBoard(tt.Width, tt.Height, ids, rand.New(rand.NewSource(1)))
+
+		if (err == nil) != tt.Valid {
+			t.Errorf("Expected %dx%d to be valid: %v, got %v", tt.Width, tt.Height, tt.Valid, err)
+			continue
+		}
+		heads := map[Coord]bool{}
+		for _, snake := range board.Snakes {
+			heads[snake.Head] = true
+		}
+		if tt.Valid && len(heads) != len(ids) {
+			t.Errorf("Expected %d different start positions on %dx%d, got %v", len(ids), tt.Width, tt.Height, heads)
+		}
+	}
+}
+
+func TestMatchIsReproducible(t *testing.T) {
+	match := Match{
+		Width:  7,
+		Height: 7,
+		Players: []Player{
+			{Name: "CircleInnerBorder", Strategy: CircleInnerBorder{}},
+			{Name: "NearestFoodStrategy", Strategy: NearestFoodStrategy{}},
+		},
+		Seed:     3,
+		MaxTurns: 500,
+		Rules:    Rules{FoodSpawnChance: 15, MinimumFood: 1},
+	}
+
+	first, err := match.Play()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := match.Play()
+
+	if first.Turns == 0 || first.Turns > match.MaxTurns {
+		t.Errorf("Expected match to end within %d turns, got %d", match.MaxTurns, first.Turns)
+	}
+	if first.Winner != second.Winner || first.Turns != second.Turns {
+		t.Errorf("Expected same result for the same seed, got %v and %v", first, second)
+	}
+}
@@ -0,0 +1,300 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+// defaultIterations is the number of iterations run by MCTS if neither a
+// deadline nor Iterations limits the search.
+const defaultIterations = 500
+
+// MCTS is a Strategy based on Monte Carlo tree search. As all snakes move at
+// the same time, every snake selects its move in a tree node independently
+// of the others by UCT (decoupled UCT). New nodes are valued by rollouts in
+// which all snakes make random safe moves. The search runs until ctx is done.
+type MCTS struct {
+	// Iterations limits the number of iterations. Without deadline and
+	// Iterations, defaultIterations are run.
+	Iterations int
+	// RolloutDepth is the number of turns simulated in each rollout.
+	RolloutDepth int
+	// Exploration weighs exploration against exploitation, √2 if zero.
+	Exploration float64
+	// Seed makes searches reproducible. A random seed is used if it is zero.
+	Seed int64
+	// Evaluator optionally scores the boards at the end of rollouts, so
+	// that snakes that are better off than their opponents are rewarded
+	// even if nobody was eliminated.
+	Evaluator Evaluator
+}
+
+// MoveStatistics are the results of a search for one move at the root.
+type MoveStatistics struct {
+	Move   SnakeDirectionType
+	Visits int
+	// Value is the mean reward of the move, between 0 (elimination) and 1
+	// (win).
+	Value float64
+}
+
+func (stats MoveStatistics) String() string {
+	return fmt.Sprintf("%s: %d visits, %.3f value", stats.Move, stats.Visits, stats.Value)
+}
+
+func (mcts MCTS) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
+	return mcts
+}
+
+// Execute makes the move that was visited most often during the search. The
+// statistics of all moves are logged at debug level.
+func (mcts MCTS) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	statistics := mcts.Search(ctx, snake, board)
+	if logger := logging.FromContext(ctx); logger.Enabled(logging.LevelDebug) {
+		var moves []string
+		for _, stats := range statistics {
+			moves = append(moves, stats.String())
+		}
+		logger.Debug("MCTS statistics: %s", strings.Join(moves, "; "))
+	}
+	trace := traceFrom(ctx)
+	for _, stats := range statistics {
+		trace.score("MCTS", stats.Move, stats.Value, fmt.Sprintf("%d visits", stats.Visits))
+	}
+	if len(statistics) == 0 || statistics[0].Visits == 0 {
+		return getSafeMove(ctx, snake, board)
+	}
+	return statistics[0].Move
+}
+
+// Search runs the tree search and returns the statistics of the snake's moves,
+// the most visited move first.
+func (mcts MCTS) Search(ctx context.Context, snake Battlesnake, board Board) []MoveStatistics {
+	ctx, cancel := withSearchDeadline(ctx)
+	defer cancel()
+
+	seed := mcts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	search := mctsSearch{
+		mcts:      mcts,
+		rand:      rand.New(rand.NewSource(seed)),
+		opponents: len(board.Snakes) - 1,
+	}
+	if search.mcts.Exploration == 0 {
+		search.mcts.Exploration = math.Sqrt2
+	}
+
+	iterations := mcts.Iterations
+	if _, ok := ctx.Deadline(); !ok && iterations == 0 {
+		iterations = defaultIterations
+	}
+
+	root := newMCTSNode(board)
+	for i := 0; (iterations == 0 || i < iterations) && !isDone(ctx); i++ {
+		search.iterate(root)
+	}
+
+	var statistics []MoveStatistics
+	for _, move := range root.moves[snake.ID] {
+		stats := root.stats[snake.ID][move]
+		statistics = append(statistics, MoveStatistics{Move: move, Visits: stats.visits, Value: stats.mean()})
+	}
+	sort.SliceStable(statistics, func(i, j int) bool {
+		return statistics[i].Visits > statistics[j].Visits
+	})
+	return statistics
+}
+
+type mctsStatistics struct {
+	visits int
+	reward float64
+}
+
+func (stats *mctsStatistics) mean() float64 {
+	if stats.visits == 0 {
+		return 0
+	}
+	return stats.reward / float64(stats.visits)
+}
+
+type mctsNode struct {
+	board  Board
+	visits int
+	// moves and stats are kept per snake, as every snake selects its move
+	// on its own.
+	moves    map[string][]SnakeDirectionType
+	stats    map[string]map[SnakeDirectionType]*mctsStatistics
+	children map[string]*mctsNode
+}
+
+func newMCTSNode(board Board) *mctsNode {
+	node := &mctsNode{
+		board:    board,
+		moves:    map[string][]SnakeDirectionType{},
+		stats:    map[string]map[SnakeDirectionType]*mctsStatistics{},
+		children: map[string]*mctsNode{},
+	}
+	for _, snake := range board.Snakes {
+		node.moves[snake.ID] = candidateMoves(snake, board)
+		node.stats[snake.ID] = map[SnakeDirectionType]*mctsStatistics{}
+		for _, move := range node.moves[snake.ID] {
+			node.stats[snake.ID][move] = &mctsStatistics{}
+		}
+	}
+	return node
+}
+
+// isGameOver tells whether a game that started with the given number of
+// opponents is over.
+func isGameOver(board Board, opponents int) bool {
+	return len(board.Snakes) == 0 || (opponents > 0 && len(board.Snakes) == 1)
+}
+
+// isGameOver is isGameOver for grids.
+func (grid *Grid) isGameOver(opponents int) bool {
+	living := 0
+	for i := range grid.snakes {
+		if grid.IsAlive(i) {
+			living++
+		}
+	}
+	return living == 0 || (opponents > 0 && living == 1)
+}
+
+type mctsSearch struct {
+	mcts MCTS
+	rand *rand.Rand
+	// opponents is the number of opponents at the root.
+	opponents int
+}
+
+type mctsStep struct {
+	node  *mctsNode
+	moves map[string]SnakeDirectionType
+}
+
+// iterate selects a path down the tree, expands it by one node, values that
+// node by a rollout and updates the statistics along the path.
+func (search mctsSearch) iterate(root *mctsNode) {
+	var path []mctsStep
+	node := root
+	for !isGameOver(node.board, search.opponents) {
+		moves := search.selectMoves(node)
+		path = append(path, mctsStep{node, moves})
+
+		key := jointMoveKey(moves)
+		child, ok := node.children[key]
+		if !ok {
+			next, _ := Rules{}.NextBoard(node.board, moves)
+			child = newMCTSNode(next)
+			node.children[key] = child
+			node = child
+			break
+		}
+		node = child
+	}
+
+	rewards := search.rollout(node.board, root.board)
+	for _, step := range path {
+		step.node.visits++
+		for id, move := range step.moves {
+			stats := step.node.stats[id][move]
+			stats.visits++
+			stats.reward += rewards[id]
+		}
+	}
+}
+
+// selectMoves lets every snake choose its move by UCB1. Moves that were never
+// tried are chosen first.
+func (search mctsSearch) selectMoves(node *mctsNode) map[string]SnakeDirectionType {
+	moves := map[string]SnakeDirectionType{}
+	for _, snake := range node.board.Snakes {
+		id := snake.ID
+		candidates := node.moves[id]
+		var untried []SnakeDirectionType
+		bestValue := math.Inf(-1)
+		for _, move := range candidates {
+			stats := node.stats[id][move]
+			if stats.visits == 0 {
+				untried = append(untried, move)
+				continue
+			}
+			value := stats.mean() + search.mcts.Exploration*math.Sqrt(math.Log(float64(node.visits))/float64(stats.visits))
+			if value > bestValue {
+				bestValue = value
+				moves[id] = move
+			}
+		}
+		if len(untried) > 0 {
+			moves[id] = untried[search.rand.Intn(len(untried))]
+		}
+	}
+	return moves
+}
+
+// rollout plays random safe moves from the board and returns the reward of
+// every snake of the root. The moves are simulated on a Grid, as rollouts are
+// where most of the turns of a search are played.
+func (search mctsSearch) rollout(start Board, root Board) map[string]float64 {
+	grid := NewGrid(start)
+	moves := make([]SnakeDirectionType, len(start.Snakes))
+	for turn := 0; turn < search.mcts.RolloutDepth && !grid.isGameOver(search.opponents); turn++ {
+		for i := range moves {
+			moves[i] = ""
+			if grid.IsAlive(i) {
+				candidates := grid.candidateMoves(i)
+				moves[i] = candidates[search.rand.Intn(len(candidates))]
+			}
+		}
+		// Candidate moves are always known to the grid.
+		grid.Move(moves)
+	}
+	board := grid.Board()
+
+	scores := map[string]float64{}
+	if search.mcts.Evaluator != nil && !isGameOver(board, search.opponents) {
+		for _, snake := range board.Snakes {
+			scores[snake.ID] = search.mcts.Evaluator.Evaluate(board, snake.ID)
+		}
+	}
+
+	rewards := map[string]float64{}
+	for _, snake := range root.Snakes {
+		if _, alive := board.snake(snake.ID); !alive {
+			continue
+		}
+		if search.opponents == 0 {
+			rewards[snake.ID] = 1
+			continue
+		}
+		// Surviving is worth half, the other half depends on how many
+		// opponents are gone or worse off.
+		beaten := len(root.Snakes) - len(board.Snakes)
+		for _, other := range board.Snakes {
+			if other.ID != snake.ID && scores[other.ID] < scores[snake.ID] {
+				beaten++
+			}
+		}
+		rewards[snake.ID] = 0.5 + 0.5*float64(beaten)/float64(search.opponents)
+	}
+	return rewards
+}
+
+func jointMoveKey(moves map[string]SnakeDirectionType) string {
+	var parts []string
+	for id, move := range moves {
+		parts = append(parts, id+":"+string(move))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+func TestMCTSReportsStatisticsPerMove(t *testing.T) {
+	snake, board := createPocketBoard()
+	mcts := MCTS{Iterations: 300, RolloutDepth: 10, Seed: 1}
+
+	statistics := mcts.Search(context.Background(), snake, board)
+
+	if len(statistics) != 2 {
+		t.Fatalf("Expected statistics for the two safe moves, got %v", statistics)
+	}
+	visits := 0
+	for _, stats := range statistics {
+		visits += stats.Visits
+		if stats.Value < 0 || stats.Value > 1 {
+			t.Errorf("Expected value between 0 and 1, got %v", stats)
+		}
+	}
+	if visits != mcts.Iterations {
+		t.Errorf("Expected %d visits in total, got %v", mcts.Iterations, statistics)
+	}
+	if statistics[0].Move != SnakeDirection.RIGHT {
+		t.Errorf("Expected move into open space to be visited most, got %v", statistics)
+	}
+}
+
+func TestMCTSLogsStatistics(t *testing.T) {
+	snake, board := createPocketBoard()
+	var buffer bytes.Buffer
+	ctx := logging.NewContext(context.Background(), logging.New(&buffer, logging.LevelDebug, logging.FormatText))
+
+	MCTS{Iterations: 50, RolloutDepth: 5, Seed: 1}.Execute(ctx, snake, board)
+
+	for _, move := range []SnakeDirectionType{SnakeDirection.RIGHT, SnakeDirection.LEFT} {
+		if !strings.Contains(buffer.String(), string(move)+": ") {
+			t.Errorf("Expected statistics of %s to be logged, got %q", move, buffer.String())
+		}
+	}
+}
+
+func TestMCTSEliminatesTrappedOpponent(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 2, Y: 0}, Body: []Coord{{X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}}, Length: 4}
+	opponent := Battlesnake{ID: "2", Health: 90, Head: Coord{X: 0, Y: 0}, Body: []Coord{{X: 0, Y: 1}, {X: 0, Y: 2}}, Length: 3}
+	board := Board{Height: 11, Width: 11, Snakes: []Battlesnake{snake, opponent}}
+
+	move := MCTS{Iterations: 500, RolloutDepth: 10, Seed: 1}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.LEFT {
+		t.Errorf("Expected to eliminate opponent with %s, got %s", SnakeDirection.LEFT, move)
+	}
+}
+
+func BenchmarkMCTS(b *testing.B) {
+	board := createGridTestBoard(RulesetStandard, rand.New(rand.NewSource(1)))
+	mcts := MCTS{Iterations: 100, RolloutDepth: 15, Seed: 1}
+
+	for n := 0; n < b.N; n++ {
+		mcts.Search(context.Background(), board.Snakes[0], board)
+	}
+}
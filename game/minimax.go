@@ -0,0 +1,288 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Scores of boards on which the game is over.
+const (
+	winScore  = 1e9
+	lossScore = -1e9
+	drawScore = lossScore / 2
+)
+
+// maxSearchDepth limits iterative deepening when there is a deadline.
+const maxSearchDepth = 64
+
+// defaultTableSize is the number of results kept in a TranspositionTable of
+// the registered Minimax strategy.
+const defaultTableSize = 1 << 16
+
+// Minimax is a Strategy that searches the moves of all snakes a number of turns
+// ahead with alpha-beta pruning. We maximize the evaluation while the
+// opponents jointly minimize it, which is plain minimax in duels and the
+// paranoid assumption with more snakes. The search deepens iteratively, one
+// turn at a time, so that the best move of a shallow search is tried first in
+// the next deeper one.
+type Minimax struct {
+	// Depth is the number of turns to look ahead. If ctx has a deadline,
+	// the search keeps deepening until the deadline instead.
+	Depth int
+	// Evaluator scores the boards at the end of the search. DefaultWeights
+	// are used if it is nil.
+	Evaluator Evaluator
+	// Table keeps search results between depths and between turns. It is
+	// optional and should only be shared by searches for the same snake.
+	Table *TranspositionTable
+}
+
+func (minimax Minimax) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
+	return minimax
+}
+
+// Execute searches the best move. If ctx is done before the search has
+// finished, the best move of the deepest finished search is made.
+func (minimax Minimax) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	ctx, cancel := withSearchDeadline(ctx)
+	defer cancel()
+
+	maxDepth := minimax.Depth
+	if _, ok := ctx.Deadline(); ok && maxDepth < maxSearchDepth {
+		maxDepth = maxSearchDepth
+	}
+
+	if minimax.Table != nil {
+		minimax.Table.newSearch()
+	}
+	trace := traceFrom(ctx)
+	bestMove := SnakeDirectionType("")
+	for depth := 1; depth <= maxDepth; depth++ {
+		move, score, completed := minimax.search(ctx, snake.ID, board, depth)
+		if !completed {
+			if bestMove == "" {
+				bestMove = move
+			}
+			break
+		}
+		if score <= lossScore/2 && bestMove != "" {
+			// Opponents that play perfectly would beat us whatever we
+			// do. Rather than giving up, keep the move of the shallower
+			// search, which hopes for a mistake after that.
+			break
+		}
+		bestMove = move
+		trace.score("Minimax", move, score, fmt.Sprintf("depth %d", depth))
+		// Searching deeper doesn't change the outcome of a won game.
+		if score >= winScore/2 {
+			break
+		}
+	}
+	if bestMove == "" {
+		return getSafeMove(ctx, snake, board)
+	}
+	return bestMove
+}
+
+// minimaxSearch is the state of a single search.
+type minimaxSearch struct {
+	ctx     context.Context
+	minimax Minimax
+	snakeID string
+	// contested tells whether there were opponents at the root, so that
+	// being the last snake standing is a win.
+	contested bool
+}
+
+// search returns the best move at the root with its score. It reports false if
+// it was cancelled before all moves were scored, returning the best move
+// found so far if there is one.
+func (minimax Minimax) search(ctx context.Context, snakeID string, board Board, depth int) (SnakeDirectionType, float64, bool) {
+	if _, ok := board.snake(snakeID); !ok {
+		return "", lossScore, false
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	search := minimaxSearch{ctx, minimax, snakeID, len(board.Snakes) > 1}
+	return search.maximize(board, depth, math.Inf(-1), math.Inf(1))
+}
+
+// maximize returns our best move on the board and its score.
+func (search minimaxSearch) maximize(board Board, depth int, alpha float64, beta float64) (SnakeDirectionType, float64, bool) {
+	if isDone(search.ctx) {
+		return "", 0, false
+	}
+	if score, over := search.gameOverScore(board, depth); over {
+		return "", score, true
+	}
+	if depth == 0 {
+		return "", search.minimax.evaluate(board, search.snakeID), true
+	}
+
+	snake, _ := board.snake(search.snakeID)
+	moves := candidateMoves(snake, board)
+
+	table := search.minimax.Table
+	var key uint64
+	if table != nil {
+		key = board.zobristHash()
+		if entry, ok := table.lookup(key, depth); ok {
+			if entry.depth >= depth {
+				switch entry.kind {
+				case exactScore:
+					return entry.move, entry.score, true
+				case lowerBound:
+					alpha = math.Max(alpha, entry.score)
+				case upperBound:
+					beta = math.Min(beta, entry.score)
+				}
+				if alpha >= beta {
+					return entry.move, entry.score, true
+				}
+			}
+			moves = moveFirst(moves, entry.move)
+		}
+	}
+
+	originalAlpha := alpha
+	bestMove := SnakeDirectionType("")
+	best := math.Inf(-1)
+	for _, move := range moves {
+		score, completed := search.minimize(board, move, depth, alpha, beta)
+		if !completed {
+			return bestMove, best, false
+		}
+		if bestMove == "" || score > best {
+			bestMove = move
+			best = score
+		}
+		alpha = math.Max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if table != nil {
+		kind := exactScore
+		if best <= originalAlpha {
+			kind = upperBound
+		} else if best >= beta {
+			kind = lowerBound
+		}
+		table.store(transposition{key: key, depth: depth, kind: kind, score: best, move: bestMove})
+	}
+	return bestMove, best, true
+}
+
+// minimize returns the score of the board after our move when the opponents
+// choose the moves that are worst for us.
+func (search minimaxSearch) minimize(board Board, move SnakeDirectionType, depth int, alpha float64, beta float64) (float64, bool) {
+	best := math.Inf(1)
+	for _, moves := range opponentMoves(search.snakeID, board) {
+		moves[search.snakeID] = move
+		next, _ := Rules{}.NextBoard(board, moves)
+		_, score, completed := search.maximize(next, depth-1, alpha, beta)
+		if !completed {
+			return 0, false
+		}
+		best = math.Min(best, score)
+		beta = math.Min(beta, score)
+		if alpha >= beta {
+			break
+		}
+	}
+	return best, true
+}
+
+// gameOverScore returns the score of the board if the game is over for us.
+// Earlier wins and later losses are preferred.
+func (search minimaxSearch) gameOverScore(board Board, depth int) (float64, bool) {
+	_, alive := board.snake(search.snakeID)
+	if !alive && len(board.Snakes) == 0 {
+		return drawScore - float64(depth), true
+	}
+	if !alive {
+		return lossScore - float64(depth), true
+	}
+	if search.contested && len(board.Snakes) == 1 {
+		return winScore + float64(depth), true
+	}
+	return 0, false
+}
+
+func (minimax Minimax) evaluate(board Board, snakeID string) float64 {
+	if minimax.Evaluator != nil {
+		return minimax.Evaluator.Evaluate(board, snakeID)
+	}
+	return defaultEvaluator.Evaluate(board, snakeID)
+}
+
+// snake returns the snake with the given ID if it is still on the board.
+func (board Board) snake(snakeID string) (Battlesnake, bool) {
+	for _, snake := range board.Snakes {
+		if snake.ID == snakeID {
+			return snake, true
+		}
+	}
+	return Battlesnake{}, false
+}
+
+// candidateMoves returns the moves of the snake that don't lose right away.
+// If there are none, the snake is doomed and a single move is returned.
+func candidateMoves(snake Battlesnake, board Board) []SnakeDirectionType {
+	var moves []SnakeDirectionType
+	for _, move := range possibleMoves {
+		if snake.Head.neighbor(move, board).isSafe(snake, board) {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		return []SnakeDirectionType{SnakeDirection.UP}
+	}
+	return moves
+}
+
+// opponentMoves returns all combinations of candidate moves of the snake's
+// opponents.
+func opponentMoves(snakeID string, board Board) []map[string]SnakeDirectionType {
+	combinations := []map[string]SnakeDirectionType{{}}
+	for _, opponent := range board.Snakes {
+		if opponent.ID == snakeID {
+			continue
+		}
+		var extended []map[string]SnakeDirectionType
+		for _, combination := range combinations {
+			for _, move := range candidateMoves(opponent, board) {
+				moves := map[string]SnakeDirectionType{opponent.ID: move}
+				for id, other := range combination {
+					moves[id] = other
+				}
+				extended = append(extended, moves)
+			}
+		}
+		combinations = extended
+	}
+	return combinations
+}
+
+// moveFirst returns the moves with the given move in front, if it is one of
+// them.
+func moveFirst(moves []SnakeDirectionType, first SnakeDirectionType) []SnakeDirectionType {
+	ordered := []SnakeDirectionType{}
+	for _, move := range moves {
+		if move == first {
+			ordered = append(ordered, move)
+		}
+	}
+	if len(ordered) == 0 {
+		return moves
+	}
+	for _, move := range moves {
+		if move != first {
+			ordered = append(ordered, move)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMinimaxAvoidsDeadEnd(t *testing.T) {
+	snake, board := createPocketBoard()
+
+	move := Minimax{Depth: 2}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.RIGHT {
+		t.Errorf("Expected to avoid dead end (%s), got %s", SnakeDirection.RIGHT, move)
+	}
+}
+
+func TestMinimaxDuel(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Snake    []Coord
+		Opponent []Coord
+		Expected []SnakeDirectionType
+	}{
+		{
+			Name:     "Expect to avoid head-to-head with longer opponent",
+			Snake:    []Coord{{X: 5, Y: 5}, {X: 5, Y: 4}, {X: 5, Y: 3}},
+			Opponent: []Coord{{X: 7, Y: 5}, {X: 8, Y: 5}, {X: 9, Y: 5}, {X: 10, Y: 5}},
+			Expected: []SnakeDirectionType{SnakeDirection.UP, SnakeDirection.LEFT},
+		},
+		{
+			Name:     "Expect to eliminate trapped shorter opponent",
+			Snake:    []Coord{{X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}},
+			Opponent: []Coord{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}},
+			Expected: []SnakeDirectionType{SnakeDirection.LEFT},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			snake := Battlesnake{ID: "1", Health: 90, Head: tt.Snake[0], Body: tt.Snake[1:], Length: int32(len(tt.Snake))}
+			opponent := Battlesnake{ID: "2", Health: 90, Head: tt.Opponent[0], Body: tt.Opponent[1:], Length: int32(len(tt.Opponent))}
+			board := Board{Height: 11, Width: 11, Snakes: []Battlesnake{snake, opponent}}
+
+			move := Minimax{Depth: 2}.Execute(context.Background(), snake, board)
+
+			for _, expected := range tt.Expected {
+				if move == expected {
+					return
+				}
+			}
+			t.Errorf("Expected one of %v, got %s", tt.Expected, move)
+		})
+	}
+}
+
+func TestMinimaxReturnsSafeMoveWhenCancelled(t *testing.T) {
+	snake, board := createPocketBoard()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	move := Minimax{Depth: 5}.Execute(ctx, snake, board)
+
+	if !snake.Head.neighbor(move, board).isSafe(snake, board) {
+		t.Errorf("Expected a safe move, got %s", move)
+	}
+}
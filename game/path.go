@@ -0,0 +1,122 @@
+package game
+
+import "container/heap"
+
+// pathNode is a cell reached while searching a path, together with the way
+// that led there.
+type pathNode struct {
+	coord Coord
+	// cost is the health spent to get here, hazards making steps expensive.
+	cost int32
+	// health is the health left when arriving here.
+	health int32
+	steps  int
+	// estimate is cost plus the least possible cost to reach a target.
+	estimate int32
+	previous *pathNode
+}
+
+type pathQueue []*pathNode
+
+func (queue pathQueue) Len() int { return len(queue) }
+func (queue pathQueue) Less(i, j int) bool {
+	if queue[i].estimate == queue[j].estimate {
+		return queue[i].steps < queue[j].steps
+	}
+	return queue[i].estimate < queue[j].estimate
+}
+func (queue pathQueue) Swap(i, j int)       { queue[i], queue[j] = queue[j], queue[i] }
+func (queue *pathQueue) Push(x interface{}) { *queue = append(*queue, x.(*pathNode)) }
+func (queue *pathQueue) Pop() interface{} {
+	old := *queue
+	last := old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return last
+}
+
+// findPath searches the cheapest path in terms of health from the snake's head
+// to the nearest of the targets using A*. Without hazards this is the shortest
+// path. Snake bodies are only crossed once their tails have moved past them,
+// and paths on which the snake would starve are discarded. The returned path
+// starts with the cell next to the head and ends with the target.
+func findPath(battlesnake Battlesnake, board Board, targets []Coord) ([]Coord, bool) {
+	if len(targets) == 0 {
+		return nil, false
+	}
+	isTarget := map[Coord]bool{}
+	for _, target := range targets {
+		isTarget[target] = true
+	}
+	heuristic := func(coord Coord) int32 {
+		least := -1
+		for _, target := range targets {
+			if distance := coord.distanceOn(target, board); least < 0 || distance < least {
+				least = distance
+			}
+		}
+		return int32(least)
+	}
+
+	vacating := vacatingTimes(board)
+	visited := map[Coord]bool{}
+	queue := &pathQueue{}
+	push := func(previous *pathNode, coord Coord, health int32) {
+		node := &pathNode{coord: coord, health: health, steps: 1, previous: previous}
+		cost := coord.healthCost(board)
+		node.cost = cost
+		if previous != nil {
+			node.cost += previous.cost
+			node.steps += previous.steps
+		}
+		node.health -= cost
+		if coord.isIn(board.Food) {
+			node.health = maxHealth
+		}
+		node.estimate = node.cost + heuristic(coord)
+		heap.Push(queue, node)
+	}
+
+	for _, move := range possibleMoves {
+		next := battlesnake.Head.neighbor(move, board)
+		if next.isSafe(battlesnake, board) {
+			push(nil, next, battlesnake.Health)
+		}
+	}
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(*pathNode)
+		if visited[current.coord] || current.health <= 0 {
+			continue
+		}
+		visited[current.coord] = true
+		if isTarget[current.coord] {
+			return current.path(), true
+		}
+		for _, move := range possibleMoves {
+			next := current.coord.neighbor(move, board)
+			if visited[next] || next.isOutsideOfArea(board) || vacating[next] > current.steps+1 {
+				continue
+			}
+			push(current, next, current.health)
+		}
+	}
+	return nil, false
+}
+
+func (node *pathNode) path() []Coord {
+	path := make([]Coord, node.steps)
+	for current := node; current != nil; current = current.previous {
+		path[current.steps-1] = current.coord
+	}
+	return path
+}
+
+// directionTo returns the move that leads from the coord to its neighbor.
+func (currentCoord Coord) directionTo(neighbor Coord, board Board) SnakeDirectionType {
+	for _, move := range possibleMoves {
+		if currentCoord.neighbor(move, board).equals(neighbor) {
+			return move
+		}
+	}
+	return SnakeDirection.UP
+}
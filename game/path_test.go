@@ -0,0 +1,64 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindPathFollowsVacatingTail(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 1, Y: 0}, Body: []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}}, Length: 3}
+	board := Board{Height: 2, Width: 2, Snakes: []Battlesnake{snake}}
+
+	path, ok := findPath(snake, board, []Coord{{X: 1, Y: 1}})
+
+	expected := []Coord{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	if !ok || len(path) != len(expected) {
+		t.Fatalf("Expected path %v, got %v", expected, path)
+	}
+	for i, coord := range expected {
+		if !path[i].equals(coord) {
+			t.Errorf("Expected path %v, got %v", expected, path)
+			break
+		}
+	}
+}
+
+func TestFindPathWithoutWay(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 0, Y: 1}, Body: []Coord{{X: 0, Y: 2}, {X: 0, Y: 3}, {X: 0, Y: 4}}, Length: 4}
+	enemy := Battlesnake{
+		ID:     "2",
+		Health: 90,
+		Head:   Coord{X: 1, Y: 0},
+		Body:   []Coord{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}, {X: 1, Y: 4}, {X: 2, Y: 4}, {X: 3, Y: 4}, {X: 4, Y: 4}},
+		Length: 8,
+	}
+	board := Board{Height: 5, Width: 5, Snakes: []Battlesnake{snake, enemy}}
+
+	if path, ok := findPath(snake, board, []Coord{{X: 3, Y: 3}}); ok {
+		t.Errorf("Expected no path out of the pocket, got %v", path)
+	}
+}
+
+func TestCollectNearestFoodByPathLength(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 5, Y: 5}, Body: []Coord{{X: 4, Y: 5}, {X: 3, Y: 5}}, Length: 3}
+	// The enemy forms a wall between the snake and the food closest to it.
+	enemy := Battlesnake{
+		ID:     "2",
+		Health: 90,
+		Head:   Coord{X: 6, Y: 8},
+		Body:   []Coord{{X: 6, Y: 7}, {X: 6, Y: 6}, {X: 6, Y: 5}, {X: 6, Y: 4}, {X: 6, Y: 3}, {X: 6, Y: 2}},
+		Length: 7,
+	}
+	board := Board{
+		Height: 11,
+		Width:  11,
+		Food:   []Coord{{X: 7, Y: 5}, {X: 5, Y: 1}},
+		Snakes: []Battlesnake{snake, enemy},
+	}
+
+	move := CollectNearestFood{}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.DOWN {
+		t.Errorf("Expected to approach food with the shortest path (%s), got %s", SnakeDirection.DOWN, move)
+	}
+}
@@ -0,0 +1,233 @@
+package game
+
+import "math/rand"
+
+// maxHealth is the health of a snake at the start of a game and right after
+// it has eaten.
+const maxHealth int32 = 100
+
+// Causes for a snake being eliminated from the game, named as in the official
+// rules.
+const (
+	EliminatedByOutOfHealth   = "out-of-health"
+	EliminatedByOutOfBounds   = "wall-collision"
+	EliminatedBySelfCollision = "snake-self-collision"
+	EliminatedByCollision     = "snake-collision"
+	EliminatedByHeadToHead    = "head-collision"
+)
+
+// Elimination describes why a snake was removed from the board.
+type Elimination struct {
+	SnakeID string
+	Cause   string
+	// By is the ID of the snake that caused the elimination, if any.
+	By string
+}
+
+// Rules advances a board by one turn according to the official standard
+// ruleset, including the damage dealt by hazards in royale games. The zero
+// value never spawns new food, which is what simulations usually want.
+type Rules struct {
+	// FoodSpawnChance is the chance in percent that a new piece of food is
+	// spawned in a turn.
+	FoodSpawnChance int
+	// MinimumFood is the amount of food that is always kept on the board.
+	MinimumFood int
+	// Rand is used to place new food. No food is spawned if it is nil.
+	Rand *rand.Rand
+}
+
+// NextBoard applies the given moves (by snake ID) to the board and returns the
+// board of the next turn together with the snakes that were eliminated. Snakes
+// without a move continue in the direction they were heading. The given board
+// is not modified.
+func (rules Rules) NextBoard(board Board, moves map[string]SnakeDirectionType) (Board, []Elimination) {
+	next := board.clone()
+
+	moveSnakes(&next, moves)
+	reduceSnakeHealth(&next)
+	damageSnakesInHazards(&next)
+	feedSnakes(&next)
+	rules.spawnFood(&next)
+	eliminations := eliminateSnakes(&next)
+
+	return next, eliminations
+}
+
+// segments returns all parts of the snake from head to tail. Boards sent by
+// the engine include the head in Body, boards built by hand often don't.
+func (battlesnake Battlesnake) segments() []Coord {
+	if len(battlesnake.Body) > 0 && battlesnake.Body[0].equals(battlesnake.Head) {
+		return battlesnake.Body
+	}
+	return append([]Coord{battlesnake.Head}, battlesnake.Body...)
+}
+
+// currentDirection returns the direction the snake moved in last turn, or up
+// if it can not be told (e.g. at the start of the game).
+func (battlesnake Battlesnake) currentDirection(board Board) SnakeDirectionType {
+	segments := battlesnake.segments()
+	for _, segment := range segments[1:] {
+		if segment.equals(battlesnake.Head) {
+			continue
+		}
+		for _, move := range possibleMoves {
+			if segment.neighbor(move, board).equals(battlesnake.Head) {
+				return move
+			}
+		}
+	}
+	return SnakeDirection.UP
+}
+
+func (board Board) clone() Board {
+	next := board
+	next.Food = append([]Coord{}, board.Food...)
+	next.Snakes = make([]Battlesnake, len(board.Snakes))
+	for i, snake := range board.Snakes {
+		snake.Body = append([]Coord{}, snake.segments()...)
+		next.Snakes[i] = snake
+	}
+	return next
+}
+
+func moveSnakes(board *Board, moves map[string]SnakeDirectionType) {
+	for i := range board.Snakes {
+		snake := &board.Snakes[i]
+		move, ok := moves[snake.ID]
+		if !ok {
+			move = snake.currentDirection(*board)
+		}
+		head := snake.Head.neighbor(move, *board)
+		snake.Body = append([]Coord{head}, snake.Body[:len(snake.Body)-1]...)
+		snake.Head = head
+	}
+}
+
+func reduceSnakeHealth(board *Board) {
+	for i := range board.Snakes {
+		board.Snakes[i].Health--
+	}
+}
+
+func damageSnakesInHazards(board *Board) {
+	for i := range board.Snakes {
+		snake := &board.Snakes[i]
+		if snake.Head.isIn(board.Food) {
+			continue
+		}
+		snake.Health -= snake.Head.hazardCount(*board) * board.hazardDamage()
+	}
+}
+
+func feedSnakes(board *Board) {
+	var remainingFood []Coord
+	for _, food := range board.Food {
+		eaten := false
+		for i := range board.Snakes {
+			snake := &board.Snakes[i]
+			if snake.Head.equals(food) {
+				snake.Health = maxHealth
+				snake.Body = append(snake.Body, snake.Body[len(snake.Body)-1])
+				eaten = true
+			}
+		}
+		if !eaten {
+			remainingFood = append(remainingFood, food)
+		}
+	}
+	board.Food = remainingFood
+	for i := range board.Snakes {
+		board.Snakes[i].Length = int32(len(board.Snakes[i].Body))
+	}
+}
+
+func (rules Rules) spawnFood(board *Board) {
+	if rules.Rand == nil {
+		return
+	}
+	amount := rules.MinimumFood - len(board.Food)
+	if amount <= 0 && rules.FoodSpawnChance > 0 && rules.Rand.Intn(100) < rules.FoodSpawnChance {
+		amount = 1
+	}
+	for i := 0; i < amount; i++ {
+		freeCoords := board.freeCoords()
+		if len(freeCoords) == 0 {
+			return
+		}
+		board.Food = append(board.Food, freeCoords[rules.Rand.Intn(len(freeCoords))])
+	}
+}
+
+// freeCoords returns all coordinates that are neither occupied by a snake nor
+// by food.
+func (board Board) freeCoords() []Coord {
+	var free []Coord
+	for x := 0; x < board.Width; x++ {
+		for y := 0; y < board.Height; y++ {
+			coord := Coord{x, y}
+			if coord.isInSnakes(board) || coord.isIn(board.Food) {
+				continue
+			}
+			free = append(free, coord)
+		}
+	}
+	return free
+}
+
+func (currentCoord Coord) isIn(coords []Coord) bool {
+	for _, coord := range coords {
+		if currentCoord.equals(coord) {
+			return true
+		}
+	}
+	return false
+}
+
+func eliminateSnakes(board *Board) []Elimination {
+	var eliminations []Elimination
+	var alive []Battlesnake
+	for _, snake := range board.Snakes {
+		if snake.Health <= 0 {
+			eliminations = append(eliminations, Elimination{SnakeID: snake.ID, Cause: EliminatedByOutOfHealth})
+			continue
+		}
+		if snake.Head.isOutsideOfArea(*board) {
+			eliminations = append(eliminations, Elimination{SnakeID: snake.ID, Cause: EliminatedByOutOfBounds})
+			continue
+		}
+		alive = append(alive, snake)
+	}
+
+	// Collisions are resolved simultaneously, so a snake that dies in this
+	// step can still take others with it.
+	var survivors []Battlesnake
+	for _, snake := range alive {
+		elimination, eliminated := collide(snake, alive)
+		if eliminated {
+			eliminations = append(eliminations, elimination)
+			continue
+		}
+		survivors = append(survivors, snake)
+	}
+	board.Snakes = survivors
+
+	return eliminations
+}
+
+func collide(snake Battlesnake, snakes []Battlesnake) (Elimination, bool) {
+	if snake.Head.isIn(snake.Body[1:]) {
+		return Elimination{SnakeID: snake.ID, Cause: EliminatedBySelfCollision, By: snake.ID}, true
+	}
+	for _, other := range snakes {
+		if other.ID != snake.ID && snake.Head.isIn(other.Body[1:]) {
+			return Elimination{SnakeID: snake.ID, Cause: EliminatedByCollision, By: other.ID}, true
+		}
+	}
+	for _, other := range snakes {
+		if other.ID != snake.ID && snake.Head.equals(other.Head) && snake.Length <= other.Length {
+			return Elimination{SnakeID: snake.ID, Cause: EliminatedByHeadToHead, By: other.ID}, true
+		}
+	}
+	return Elimination{}, false
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRulesMoveSnakes(t *testing.T) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 50,
+		Head:   Coord{X: 2, Y: 2},
+		Body:   []Coord{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}},
+		Length: 3,
+	}
+	board := Board{Height: 5, Width: 5, Snakes: []Battlesnake{snake}}
+
+	next, eliminations := Rules{}.NextBoard(board, map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT})
+
+	if len(eliminations) != 0 {
+		t.Fatalf("Expected no eliminations, got %v", eliminations)
+	}
+	moved := next.Snakes[0]
+	expectedBody := []Coord{{X: 3, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}}
+	if !moved.Head.equals(expectedBody[0]) || len(moved.Body) != len(expectedBody) {
+		t.Fatalf("Expected body %v, got head %v and body %v", expectedBody, moved.Head, moved.Body)
+	}
+	for i, coord := range expectedBody {
+		if !moved.Body[i].equals(coord) {
+			t.Errorf("Expected body %v, got %v", expectedBody, moved.Body)
+			break
+		}
+	}
+	if moved.Health != 49 {
+		t.Errorf("Expected health to decay to 49, got %d", moved.Health)
+	}
+	if board.Snakes[0].Head.X != 2 {
+		t.Errorf("Expected original board to be untouched")
+	}
+}
+
+func TestRulesFeedSnakes(t *testing.T) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 10,
+		Head:   Coord{X: 2, Y: 2},
+		Body:   []Coord{{X: 2, Y: 1}, {X: 2, Y: 0}},
+		Length: 3,
+	}
+	board := Board{Height: 5, Width: 5, Food: []Coord{{X: 2, Y: 3}, {X: 0, Y: 0}}, Snakes: []Battlesnake{snake}}
+
+	next, _ := Rules{}.NextBoard(board, map[string]SnakeDirectionType{"1": SnakeDirection.UP})
+
+	fed := next.Snakes[0]
+	if fed.Health != maxHealth {
+		t.Errorf("Expected health to be restored, got %d", fed.Health)
+	}
+	if fed.Length != 4 || len(fed.Body) != 4 {
+		t.Errorf("Expected snake to grow to 4, got length %d and body %v", fed.Length, fed.Body)
+	}
+	if len(next.Food) != 1 || !next.Food[0].equals(Coord{X: 0, Y: 0}) {
+		t.Errorf("Expected eaten food to be removed, got %v", next.Food)
+	}
+}
+
+func TestRulesSpawnFood(t *testing.T) {
+	board := Board{Height: 5, Width: 5}
+	rules := Rules{MinimumFood: 3, Rand: rand.New(rand.NewSource(1))}
+
+	next, _ := rules.NextBoard(board, nil)
+
+	if len(next.Food) != 3 {
+		t.Errorf("Expected 3 food, got %v", next.Food)
+	}
+}
+
+func TestRulesEliminateSnakes(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Snakes   []Battlesnake
+		Moves    map[string]SnakeDirectionType
+		Expected []Elimination
+	}{
+		{
+			Name: "Snake leaving the board collides with wall",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 100, Head: Coord{X: 0, Y: 2}, Body: []Coord{{X: 1, Y: 2}}, Length: 2},
+			},
+			Moves:    map[string]SnakeDirectionType{"1": SnakeDirection.LEFT},
+			Expected: []Elimination{{SnakeID: "1", Cause: EliminatedByOutOfBounds}},
+		},
+		{
+			Name: "Snake without health starves",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 1, Head: Coord{X: 2, Y: 2}, Body: []Coord{{X: 1, Y: 2}}, Length: 2},
+			},
+			Moves:    map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT},
+			Expected: []Elimination{{SnakeID: "1", Cause: EliminatedByOutOfHealth}},
+		},
+		{
+			Name: "Snake biting itself collides with itself",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 100, Head: Coord{X: 1, Y: 1}, Body: []Coord{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 3, Y: 1}}, Length: 5},
+			},
+			Moves:    map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT},
+			Expected: []Elimination{{SnakeID: "1", Cause: EliminatedBySelfCollision, By: "1"}},
+		},
+		{
+			Name: "Snake may follow its own tail",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 100, Head: Coord{X: 1, Y: 1}, Body: []Coord{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}}, Length: 4},
+			},
+			Moves: map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT},
+		},
+		{
+			Name: "Snake moving into other body collides",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 100, Head: Coord{X: 1, Y: 1}, Body: []Coord{{X: 0, Y: 1}}, Length: 2},
+				{ID: "2", Health: 100, Head: Coord{X: 2, Y: 3}, Body: []Coord{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}}, Length: 4},
+			},
+			Moves:    map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT, "2": SnakeDirection.UP},
+			Expected: []Elimination{{SnakeID: "1", Cause: EliminatedByCollision, By: "2"}},
+		},
+		{
+			Name: "Shorter snake loses head-to-head",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 100, Head: Coord{X: 1, Y: 1}, Body: []Coord{{X: 0, Y: 1}}, Length: 2},
+				{ID: "2", Health: 100, Head: Coord{X: 3, Y: 1}, Body: []Coord{{X: 4, Y: 1}, {X: 4, Y: 2}}, Length: 3},
+			},
+			Moves:    map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT, "2": SnakeDirection.LEFT},
+			Expected: []Elimination{{SnakeID: "1", Cause: EliminatedByHeadToHead, By: "2"}},
+		},
+		{
+			Name: "Snakes of equal length both lose head-to-head",
+			Snakes: []Battlesnake{
+				{ID: "1", Health: 100, Head: Coord{X: 1, Y: 1}, Body: []Coord{{X: 0, Y: 1}}, Length: 2},
+				{ID: "2", Health: 100, Head: Coord{X: 3, Y: 1}, Body: []Coord{{X: 4, Y: 1}}, Length: 2},
+			},
+			Moves: map[string]SnakeDirectionType{"1": SnakeDirection.RIGHT, "2": SnakeDirection.LEFT},
+			Expected: []Elimination{
+				{SnakeID: "1", Cause: EliminatedByHeadToHead, By: "2"},
+				{SnakeID: "2", Cause: EliminatedByHeadToHead, By: "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			board := Board{Height: 5, Width: 5, Snakes: tt.Snakes}
+
+			next, eliminations := Rules{}.NextBoard(board, tt.Moves)
+
+			if len(eliminations) != len(tt.Expected) {
+				t.Fatalf("Expected eliminations %v, got %v", tt.Expected, eliminations)
+			}
+			for i, elimination := range eliminations {
+				if elimination != tt.Expected[i] {
+					t.Errorf("Expected eliminations %v, got %v", tt.Expected, eliminations)
+				}
+			}
+			if len(next.Snakes) != len(tt.Snakes)-len(tt.Expected) {
+				t.Errorf("Expected eliminated snakes to be removed from board, got %v", next.Snakes)
+			}
+		})
+	}
+}
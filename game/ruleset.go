@@ -0,0 +1,39 @@
+package game
+
+// Names of the official rulesets.
+const (
+	RulesetStandard    = "standard"
+	RulesetSolo        = "solo"
+	RulesetRoyale      = "royale"
+	RulesetSquad       = "squad"
+	RulesetConstrictor = "constrictor"
+	RulesetWrapped     = "wrapped"
+)
+
+// Ruleset describes the rules of the game being played.
+type Ruleset struct {
+	Name     string          `json:"name"`
+	Version  string          `json:"version"`
+	Settings RulesetSettings `json:"settings"`
+}
+
+type RulesetSettings struct {
+	FoodSpawnChance     int            `json:"foodSpawnChance"`
+	MinimumFood         int            `json:"minimumFood"`
+	HazardDamagePerTurn int            `json:"hazardDamagePerTurn"`
+	HazardMap           string         `json:"hazardMap,omitempty"`
+	HazardMapAuthor     string         `json:"hazardMapAuthor,omitempty"`
+	Royale              RoyaleSettings `json:"royale"`
+	Squad               SquadSettings  `json:"squad"`
+}
+
+type RoyaleSettings struct {
+	ShrinkEveryNTurns int `json:"shrinkEveryNTurns"`
+}
+
+type SquadSettings struct {
+	AllowBodyCollisions bool `json:"allowBodyCollisions"`
+	SharedElimination   bool `json:"sharedElimination"`
+	SharedHealth        bool `json:"sharedHealth"`
+	SharedLength        bool `json:"sharedLength"`
+}
@@ -1,18 +1,26 @@
 package game
 
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
 type Strategy interface {
-	ExecuteNextStep(Battlesnake, Board) Action
+	ExecuteNextStep(context.Context, Battlesnake, Board) Action
 }
 
 type NearestFoodStrategy struct{}
 
-func (NearestFoodStrategy) ExecuteNextStep(snake Battlesnake, board Board) Action {
+func (NearestFoodStrategy) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
 	return CollectNearestFood{}
 }
 
 type FoodOnlyWhenHealthLow struct{}
 
-func (FoodOnlyWhenHealthLow) ExecuteNextStep(snake Battlesnake, board Board) Action {
+func (FoodOnlyWhenHealthLow) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
 	if snake.Health > int32(board.Height) {
 		return MakeSafeMove{}
 	}
@@ -21,13 +29,102 @@ func (FoodOnlyWhenHealthLow) ExecuteNextStep(snake Battlesnake, board Board) Act
 
 type CircleInnerBorder struct{}
 
-func (CircleInnerBorder) ExecuteNextStep(snake Battlesnake, board Board) Action {
-	if snake.Health < int32(board.Height) {
+func (CircleInnerBorder) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
+	return AvoidDeadEnds{ContestHeads{circleInnerBorder(snake, board)}}
+}
+
+// isHungry tells whether the snake should go for food before anything else.
+// Its health then barely lasts for a walk across the board.
+func isHungry(snake Battlesnake, board Board) bool {
+	return snake.Health < int32(board.Height)
+}
+
+func circleInnerBorder(snake Battlesnake, board Board) Action {
+	if isHungry(snake, board) {
 		return CollectNearestFood{}
 	}
+	if snake.Head.isHazard(board) {
+		return LeaveHazard{}
+	}
+	if !board.geometry().hasWalls() {
+		// Without walls there is no border to circle, so just keep to
+		// the open space.
+		return AvoidDeadEnds{}
+	}
 	if !snake.Head.isAtEdge(snake, board) {
 		return ApproachBorder{}
 	}
 
 	return FollowBorder{}
 }
+
+// strategies creates the known strategies that don't score boards.
+var strategies = map[string]func() Strategy{
+	"NearestFoodStrategy":   func() Strategy { return NearestFoodStrategy{} },
+	"FoodOnlyWhenHealthLow": func() Strategy { return FoodOnlyWhenHealthLow{} },
+	"CircleInnerBorder":     func() Strategy { return CircleInnerBorder{} },
+	"MaximizeTerritory":     func() Strategy { return MaximizeTerritory{} },
+}
+
+// evaluatedStrategies creates the known strategies that score boards with the
+// given evaluator, which may be nil to use DefaultWeights.
+var evaluatedStrategies = map[string]func(evaluator Evaluator) Strategy{
+	"Greedy": func(evaluator Evaluator) Strategy {
+		return Greedy{Evaluator: evaluator}
+	},
+	"Minimax": func(evaluator Evaluator) Strategy {
+		return Minimax{Depth: 2, Evaluator: evaluator, Table: NewTranspositionTable(defaultTableSize)}
+	},
+	"MCTS": func(evaluator Evaluator) Strategy {
+		// MCTS only scores the ends of rollouts if it has an evaluator.
+		if evaluator == nil {
+			evaluator = defaultEvaluator
+		}
+		return MCTS{RolloutDepth: 15, Evaluator: evaluator}
+	},
+}
+
+// NewStrategy creates the strategy with the given name.
+func NewStrategy(name string) (Strategy, error) {
+	return NewEvaluatedStrategy(name, nil)
+}
+
+// NewEvaluatedStrategy creates the strategy with the given name. If it scores
+// boards, it uses the evaluator, or DefaultWeights if that is nil.
+func NewEvaluatedStrategy(name string, evaluator Evaluator) (Strategy, error) {
+	if create, ok := evaluatedStrategies[name]; ok {
+		return create(evaluator), nil
+	}
+	if create, ok := strategies[name]; ok {
+		return create(), nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q, available are %s", name, strings.Join(StrategyNames(), ", "))
+}
+
+// IsEvaluatedStrategy tells whether the strategy with the given name scores
+// boards with an Evaluator.
+func IsEvaluatedStrategy(name string) bool {
+	_, ok := evaluatedStrategies[name]
+	return ok
+}
+
+// StrategyNames returns the names of all strategies known to NewStrategy.
+func StrategyNames() []string {
+	var names []string
+	for name := range strategies {
+		names = append(names, name)
+	}
+	for name := range evaluatedStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StrategyName returns the name of the strategy's type, e.g. "CircleInnerBorder".
+func StrategyName(strategy Strategy) string {
+	if strategy == nil {
+		return ""
+	}
+	return reflect.Indirect(reflect.ValueOf(strategy)).Type().Name()
+}
@@ -0,0 +1,57 @@
+package game
+
+import "testing"
+
+func TestIsEvaluatedStrategy(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Evaluated bool
+	}{
+		{"CircleInnerBorder", false},
+		{"NearestFoodStrategy", false},
+		{"FoodOnlyWhenHealthLow", false},
+		{"MaximizeTerritory", false},
+		{"Greedy", true},
+		{"Minimax", true},
+		{"MCTS", true},
+		{"Unknown", false},
+	}
+
+	for _, tt := range tests {
+		if IsEvaluatedStrategy(tt.Name) != tt.Evaluated {
+			t.Errorf("Expected %s to use an evaluator: %v", tt.Name, tt.Evaluated)
+		}
+	}
+	if len(StrategyNames()) != len(tests)-1 {
+		t.Errorf("Expected every strategy to be covered, got %v", StrategyNames())
+	}
+}
+
+func TestEvaluatedStrategiesUseDefaultWeights(t *testing.T) {
+	strategy, err := NewStrategy("MCTS")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mcts, ok := strategy.(MCTS); !ok || mcts.Evaluator == nil {
+		t.Errorf("Expected MCTS to score rollouts with the default weights, got %+v", strategy)
+	}
+}
+
+func TestIsHungry(t *testing.T) {
+	tests := []struct {
+		Health   int32
+		Expected bool
+	}{
+		{10, true},
+		{11, false},
+		{100, false},
+	}
+	board := Board{Width: 11, Height: 11}
+
+	for _, tt := range tests {
+		if hungry := isHungry(Battlesnake{Health: tt.Health}, board); hungry != tt.Expected {
+			t.Errorf("Expected a snake with health %d to be hungry: %v, got %v", tt.Health, tt.Expected, hungry)
+		}
+	}
+}
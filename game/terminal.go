@@ -0,0 +1,115 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences used by RenderTerminal.
+const (
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiFood       = "\x1b[38;5;203m"
+	ansiEmpty      = "\x1b[38;5;240m"
+	ansiHazardBack = "\x1b[48;5;237m"
+)
+
+// ansiSnakeColors are used for snakes without a valid color.
+var ansiSnakeColors = []string{
+	"\x1b[38;5;39m",
+	"\x1b[38;5;214m",
+	"\x1b[38;5;171m",
+	"\x1b[38;5;48m",
+	"\x1b[38;5;226m",
+	"\x1b[38;5;51m",
+}
+
+var headGlyphs = map[SnakeDirectionType]string{
+	SnakeDirection.UP:    "▲",
+	SnakeDirection.DOWN:  "▼",
+	SnakeDirection.LEFT:  "◀",
+	SnakeDirection.RIGHT: "▶",
+}
+
+const (
+	bodyGlyph  = "■"
+	tailGlyph  = "▪"
+	foodGlyph  = "●"
+	emptyGlyph = "·"
+)
+
+// RenderTerminal draws the board with ANSI colors for terminals, the top row
+// first. Snakes are drawn in their configured colors, with the head pointing
+// in the direction they moved last. Hazards have a gray background. A legend
+// below the board names the snakes and marks the one with the given ID.
+func RenderTerminal(board Board, youID string) string {
+	cells := make([][]string, board.Height)
+	for y := range cells {
+		cells[y] = make([]string, board.Width)
+		for x := range cells[y] {
+			cells[y][x] = ansiEmpty + emptyGlyph
+		}
+	}
+	draw := func(coord Coord, cell string) {
+		if !coord.isOutsideOfArea(board) {
+			cells[coord.Y][coord.X] = cell
+		}
+	}
+	for _, food := range board.Food {
+		draw(food, ansiFood+foodGlyph)
+	}
+
+	var legend strings.Builder
+	for i, snake := range board.Snakes {
+		color := ansiColor(snake.Customizations.Color)
+		if color == "" {
+			color = ansiSnakeColors[i%len(ansiSnakeColors)]
+		}
+		segments := snake.segments()
+		for j := len(segments) - 1; j > 0; j-- {
+			glyph := bodyGlyph
+			if segments[j].equals(segments[len(segments)-1]) {
+				glyph = tailGlyph
+			}
+			draw(segments[j], color+glyph)
+		}
+		draw(snake.Head, ansiBold+color+headGlyphs[snake.currentDirection(board)])
+
+		name := snake.Name
+		if name == "" {
+			name = snake.ID
+		}
+		if snake.ID == youID {
+			name += " (you)"
+		}
+		fmt.Fprintf(&legend, "%s%s%s %s  health %d  length %d\n", color, headGlyphs[SnakeDirection.RIGHT], ansiReset, name, snake.Health, snake.Length)
+	}
+
+	var rendered strings.Builder
+	for y := board.Height - 1; y >= 0; y-- {
+		for x := 0; x < board.Width; x++ {
+			background := ""
+			if (Coord{x, y}).isHazard(board) {
+				background = ansiHazardBack
+			}
+			rendered.WriteString(background + cells[y][x] + " " + ansiReset)
+		}
+		rendered.WriteString("\n")
+	}
+	rendered.WriteString(legend.String())
+	return rendered.String()
+}
+
+// ansiColor returns the escape sequence for a color like "#ff5978", or an
+// empty string if it is not such a color.
+func ansiColor(color string) string {
+	if len(color) != 7 || color[0] != '#' {
+		return ""
+	}
+	rgb, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", rgb>>16, rgb>>8&0xff, rgb&0xff)
+}
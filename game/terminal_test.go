@@ -0,0 +1,62 @@
+package game
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var ansiSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestRenderTerminal(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		. * . .
+		. A < ~
+		B . ^ ~
+		^ . . .
+		A: name=me
+		B: name=enemy health=42
+	`)
+	board.Snakes[0].Customizations.Color = "#ff5978"
+
+	rendered := RenderTerminal(board, snake.ID)
+
+	expected := strings.Join([]string{
+		"· ● · · ",
+		"· ◀ ■ · ",
+		"▲ · ▪ · ",
+		"▪ · · · ",
+		"▶ me (you)  health 100  length 3",
+		"▶ enemy  health 42  length 2",
+		"",
+	}, "\n")
+	if plain := ansiSequence.ReplaceAllString(rendered, ""); plain != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, plain)
+	}
+	if !strings.Contains(rendered, "\x1b[38;2;255;89;120m") {
+		t.Errorf("Expected the snake's color to be used:\n%q", rendered)
+	}
+	if !strings.Contains(rendered, ansiHazardBack+ansiEmpty+emptyGlyph) {
+		t.Errorf("Expected hazards to have a background:\n%q", rendered)
+	}
+}
+
+func TestANSIColor(t *testing.T) {
+	tests := []struct {
+		Color    string
+		Expected string
+	}{
+		{"#ff5978", "\x1b[38;2;255;89;120m"},
+		{"#000000", "\x1b[38;2;0;0;0m"},
+		{"ff5978", ""},
+		{"#ff59", ""},
+		{"#gg5978", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if color := ansiColor(tt.Color); color != tt.Expected {
+			t.Errorf("Expected %q for %q, got %q", tt.Expected, tt.Color, color)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"context"
+	"fmt"
+)
+
+// Territory describes the part of the board a snake controls, i.e. the cells
+// it reaches before any other snake.
+type Territory struct {
+	// Cells is the number of cells in the territory.
+	Cells int
+	// Food is the number of pieces of food in the territory.
+	Food int
+	// Contested is the number of cells the snake reaches at the same time
+	// as another snake. They belong to the longest of those snakes, or to
+	// nobody if there are several of them.
+	Contested int
+}
+
+// Territories divides the board between the snakes by a breadth-first search
+// from all heads at the same time (a Voronoi partition). Cells occupied by
+// snakes can be claimed once the tails have moved past them.
+func Territories(board Board) map[string]Territory {
+	return territoriesAfterMove(board, "", "")
+}
+
+// territoriesAfterMove divides the board like Territories, but the snake with
+// the given ID can only make the given move first.
+func territoriesAfterMove(board Board, snakeID string, firstMove SnakeDirectionType) map[string]Territory {
+	vacating := vacatingTimes(board)
+	territories := map[string]Territory{}
+	arrivals := map[Coord]int{}
+	frontiers := map[string][]Coord{}
+	for _, snake := range board.Snakes {
+		territories[snake.ID] = Territory{}
+		arrivals[snake.Head] = 0
+		frontiers[snake.ID] = []Coord{snake.Head}
+	}
+
+	for step := 1; ; step++ {
+		claims := map[Coord][]Battlesnake{}
+		var claimed []Coord
+		for _, snake := range board.Snakes {
+			for _, current := range frontiers[snake.ID] {
+				for _, move := range possibleMoves {
+					if step == 1 && snake.ID == snakeID && move != firstMove {
+						continue
+					}
+					next := current.neighbor(move, board)
+					if _, reached := arrivals[next]; reached || next.isOutsideOfArea(board) || vacating[next] > step {
+						continue
+					}
+					if isClaimedBy(claims[next], snake.ID) {
+						continue
+					}
+					if len(claims[next]) == 0 {
+						claimed = append(claimed, next)
+					}
+					claims[next] = append(claims[next], snake)
+				}
+			}
+		}
+		if len(claimed) == 0 {
+			return territories
+		}
+
+		frontiers = map[string][]Coord{}
+		for _, coord := range claimed {
+			arrivals[coord] = step
+			claimants := claims[coord]
+			if len(claimants) > 1 {
+				for _, claimant := range claimants {
+					territory := territories[claimant.ID]
+					territory.Contested++
+					territories[claimant.ID] = territory
+				}
+			}
+			owner, ok := longestSnake(claimants)
+			if !ok {
+				continue
+			}
+			territory := territories[owner.ID]
+			territory.Cells++
+			if coord.isIn(board.Food) {
+				territory.Food++
+			}
+			territories[owner.ID] = territory
+			frontiers[owner.ID] = append(frontiers[owner.ID], coord)
+		}
+	}
+}
+
+func isClaimedBy(claimants []Battlesnake, snakeID string) bool {
+	for _, claimant := range claimants {
+		if claimant.ID == snakeID {
+			return true
+		}
+	}
+	return false
+}
+
+// longestSnake returns the snake that is longer than all others. It reports
+// false if there is no such snake.
+func longestSnake(snakes []Battlesnake) (Battlesnake, bool) {
+	longest := snakes[0]
+	unique := true
+	for _, snake := range snakes[1:] {
+		if snake.Length > longest.Length {
+			longest = snake
+			unique = true
+		} else if snake.Length == longest.Length {
+			unique = false
+		}
+	}
+	return longest, unique
+}
+
+// MaximizeTerritory is a Strategy that claims as much of the board as it can,
+// collecting food only when health is low.
+type MaximizeTerritory struct{}
+
+func (MaximizeTerritory) ExecuteNextStep(ctx context.Context, snake Battlesnake, board Board) Action {
+	if isHungry(snake, board) {
+		return AvoidDeadEnds{CollectNearestFood{}}
+	}
+	return AvoidDeadEnds{ClaimTerritory{}}
+}
+
+// ClaimTerritory makes the safe move after which the snake's territory is
+// largest, preferring territories with more food.
+type ClaimTerritory struct{}
+
+func (ClaimTerritory) Execute(ctx context.Context, snake Battlesnake, board Board) SnakeDirectionType {
+	trace := traceFrom(ctx)
+	bestMove := SnakeDirectionType("")
+	var best Territory
+	for _, move := range possibleMoves {
+		if newCoord := snake.Head.neighbor(move, board); !newCoord.isSafe(snake, board) {
+			trace.reject("ClaimTerritory", move, newCoord.unsafeReason(snake, board))
+			continue
+		}
+		territory := territoriesAfterMove(board, snake.ID, move)[snake.ID]
+		trace.score("ClaimTerritory", move, float64(territory.Cells), fmt.Sprintf("%d food", territory.Food))
+		if bestMove == "" || territory.Cells > best.Cells || (territory.Cells == best.Cells && territory.Food > best.Food) {
+			bestMove = move
+			best = territory
+		}
+	}
+	if bestMove == "" {
+		return getSafeMove(ctx, snake, board)
+	}
+	return bestMove
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTerritories(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Lengths  [2]int32
+		Food     []Coord
+		Expected [2]Territory
+	}{
+		{
+			Name:     "Expect contested cell of equally long snakes to belong to nobody",
+			Lengths:  [2]int32{1, 1},
+			Expected: [2]Territory{{Cells: 1, Contested: 1}, {Cells: 1, Contested: 1}},
+		},
+		{
+			Name:     "Expect contested cell to belong to longer snake",
+			Lengths:  [2]int32{1, 2},
+			Expected: [2]Territory{{Cells: 1, Contested: 1}, {Cells: 2, Contested: 1}},
+		},
+		{
+			Name:     "Expect food in territory to be counted",
+			Lengths:  [2]int32{1, 1},
+			Food:     []Coord{{X: 1, Y: 0}, {X: 2, Y: 0}},
+			Expected: [2]Territory{{Cells: 1, Food: 1, Contested: 1}, {Cells: 1, Contested: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			board := Board{Height: 1, Width: 5, Food: tt.Food, Snakes: []Battlesnake{
+				{ID: "1", Health: 90, Head: Coord{X: 0, Y: 0}, Length: tt.Lengths[0]},
+				{ID: "2", Health: 90, Head: Coord{X: 4, Y: 0}, Length: tt.Lengths[1]},
+			}}
+
+			territories := Territories(board)
+
+			for i, snake := range board.Snakes {
+				if territories[snake.ID] != tt.Expected[i] {
+					t.Errorf("Expected territory %+v of snake %s, got %+v", tt.Expected[i], snake.ID, territories[snake.ID])
+				}
+			}
+		})
+	}
+}
+
+func TestTerritoriesClaimVacatingCells(t *testing.T) {
+	snake := Battlesnake{
+		ID:     "1",
+		Health: 90,
+		Head:   Coord{X: 1, Y: 0},
+		Body:   []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}},
+		Length: 3,
+	}
+	board := Board{Height: 2, Width: 2, Snakes: []Battlesnake{snake}}
+
+	if territory := Territories(board)[snake.ID]; territory.Cells != 3 {
+		t.Errorf("Expected all cells but the head to be claimed, got %+v", territory)
+	}
+}
+
+func TestClaimTerritory(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 2, Y: 0}, Length: 1}
+	opponent := Battlesnake{ID: "2", Health: 90, Head: Coord{X: 6, Y: 0}, Length: 1}
+	board := Board{Height: 1, Width: 7, Snakes: []Battlesnake{snake, opponent}}
+
+	move := ClaimTerritory{}.Execute(context.Background(), snake, board)
+
+	if move != SnakeDirection.LEFT {
+		t.Errorf("Expected to claim the uncontested cells on the left, got %s", move)
+	}
+}
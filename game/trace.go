@@ -0,0 +1,141 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Kinds of trace events.
+const (
+	// TraceCandidate is a move that was considered.
+	TraceCandidate = "candidate"
+	// TraceScore is a move together with the score it was given.
+	TraceScore = "score"
+	// TraceRejected is a move that was ruled out, with the reason.
+	TraceRejected = "rejected"
+	// TraceFallback is a move that was made because the preferred way to
+	// choose one failed, with the reason.
+	TraceFallback = "fallback"
+	// TraceChosen is a move that was decided on.
+	TraceChosen = "chosen"
+)
+
+// TraceEvent is a step of the decision for a move.
+type TraceEvent struct {
+	// Source is the strategy or action the event comes from.
+	Source string             `json:"source"`
+	Kind   string             `json:"kind"`
+	Move   SnakeDirectionType `json:"move,omitempty"`
+	Score  *float64           `json:"score,omitempty"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+func (event TraceEvent) String() string {
+	description := event.Source + " " + event.Kind
+	if event.Move != "" {
+		description += " " + string(event.Move)
+	}
+	if event.Score != nil {
+		description += fmt.Sprintf(" %.4g", *event.Score)
+	}
+	if event.Reason != "" {
+		description += ": " + event.Reason
+	}
+	return description
+}
+
+// Trace collects the events of the decision for a move. Strategies and actions
+// add to the trace of their context, if it has one. It is safe for concurrent
+// use, as strategies may still run after the move was made.
+type Trace struct {
+	mu     sync.Mutex
+	events []TraceEvent
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context that collects the events of decisions made with
+// it in the returned Trace.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+// traceFrom returns the trace of the context, or nil if decisions are not
+// traced. All methods of Trace may be called on nil.
+func traceFrom(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// Events returns the events collected so far.
+func (trace *Trace) Events() []TraceEvent {
+	if trace == nil {
+		return nil
+	}
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	return append([]TraceEvent(nil), trace.events...)
+}
+
+func (trace *Trace) String() string {
+	var events []string
+	for _, event := range trace.Events() {
+		events = append(events, event.String())
+	}
+	return strings.Join(events, "; ")
+}
+
+// MarshalJSON writes the events collected so far.
+func (trace *Trace) MarshalJSON() ([]byte, error) {
+	return json.Marshal(trace.Events())
+}
+
+func (trace *Trace) add(event TraceEvent) {
+	if trace == nil {
+		return
+	}
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	trace.events = append(trace.events, event)
+}
+
+func (trace *Trace) candidate(source string, move SnakeDirectionType, reason string) {
+	trace.add(TraceEvent{Source: source, Kind: TraceCandidate, Move: move, Reason: reason})
+}
+
+func (trace *Trace) score(source string, move SnakeDirectionType, score float64, reason string) {
+	trace.add(TraceEvent{Source: source, Kind: TraceScore, Move: move, Score: &score, Reason: reason})
+}
+
+func (trace *Trace) reject(source string, move SnakeDirectionType, reason string) {
+	trace.add(TraceEvent{Source: source, Kind: TraceRejected, Move: move, Reason: reason})
+}
+
+func (trace *Trace) fallback(source string, move SnakeDirectionType, reason string) {
+	trace.add(TraceEvent{Source: source, Kind: TraceFallback, Move: move, Reason: reason})
+}
+
+func (trace *Trace) choose(source string, move SnakeDirectionType, reason string) {
+	trace.add(TraceEvent{Source: source, Kind: TraceChosen, Move: move, Reason: reason})
+}
+
+// unsafeReason tells why moving onto the coord is not safe for the snake, or
+// returns an empty string if it is safe.
+func (currentCoord Coord) unsafeReason(battlesnake Battlesnake, board Board) string {
+	switch {
+	case currentCoord.isSafe(battlesnake, board):
+		return ""
+	case currentCoord.isOutsideOfArea(board):
+		return "out of area"
+	case currentCoord.isInSnake(battlesnake):
+		return "in own body"
+	case currentCoord.isInSnakes(board):
+		return "in snake body"
+	default:
+		return "deadly hazard"
+	}
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTraceOfSafeMove(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		. . .
+		B . .
+		A < <
+	`)
+	ctx, trace := WithTrace(context.Background())
+
+	move := getSafeMove(ctx, snake, board)
+
+	expected := []string{
+		"SafeMove rejected up: in snake body",
+		"SafeMove rejected right: in own body",
+		"SafeMove rejected down: out of area",
+		"SafeMove rejected left: out of area",
+		"SafeMove fallback up: No safe move found",
+	}
+	var events []string
+	for _, event := range trace.Events() {
+		events = append(events, event.String())
+	}
+	if move != SnakeDirection.UP || !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %s with %v", expected, move, events)
+	}
+}
+
+func TestTraceOfNextMove(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		. . . .
+		. A < .
+		. . . .
+	`)
+	strategic := StrategicBattlesnake{Snake: snake, Strategy: Greedy{}}
+	ctx, trace := WithTrace(context.Background())
+
+	move, err := strategic.NextMove(ctx, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := trace.Events()
+	if len(events) != 5 {
+		t.Fatalf("Expected three scores, a rejected move and the choice, got %s", trace)
+	}
+	if events[0].Kind != TraceScore || events[0].Score == nil {
+		t.Errorf("Expected a score, got %+v", events[0])
+	}
+	if rejected := events[1]; rejected.Kind != TraceRejected || rejected.Move != SnakeDirection.RIGHT || rejected.Reason != "in own body" {
+		t.Errorf("Expected right to be rejected, got %+v", rejected)
+	}
+	last := events[len(events)-1]
+	if last.Kind != TraceChosen || last.Source != "Greedy" || last.Move != move || last.Reason != "Greedy" {
+		t.Errorf("Expected the move to be chosen by Greedy, got %+v", last)
+	}
+}
+
+func TestTraceIsOptional(t *testing.T) {
+	var trace *Trace
+	trace.choose("Greedy", SnakeDirection.UP, "")
+	if events := trace.Events(); events != nil {
+		t.Errorf("Expected no events, got %v", events)
+	}
+	if traceFrom(context.Background()) != nil {
+		t.Errorf("Expected no trace without WithTrace")
+	}
+}
+
+func TestUnsafeReason(t *testing.T) {
+	board, snake := mustParseBoard(t, `
+		B < ~
+		. A .
+		> ^ .
+		A: health=1
+	`)
+	board.Ruleset.Settings.HazardDamagePerTurn = 14
+
+	tests := []struct {
+		Coord    Coord
+		Expected string
+	}{
+		{Coord{0, 1}, ""},
+		{Coord{1, 0}, "in own body"},
+		{Coord{1, 2}, "in snake body"},
+		{Coord{2, 2}, "deadly hazard"},
+		{Coord{3, 1}, "out of area"},
+	}
+
+	for _, tt := range tests {
+		if reason := tt.Coord.unsafeReason(snake, board); reason != tt.Expected {
+			t.Errorf("Expected %q for %v, got %q", tt.Expected, tt.Coord, reason)
+		}
+	}
+}
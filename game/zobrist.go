@@ -0,0 +1,188 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+)
+
+const (
+	// zobristCells is the number of cells with their own keys. Larger boards
+	// share keys between cells, which only makes collisions more likely.
+	zobristCells = 32 * 32
+	// zobristSnakes is the number of snakes with their own keys.
+	zobristSnakes = 8
+	// healthBucketSize is the range of health values hashed alike.
+	healthBucketSize = 10
+	zobristLengths   = 64
+)
+
+// zobristKeys holds a random key for every feature a board can have. The hash
+// of a board is the XOR of the keys of its features, so boards that differ
+// only slightly have completely different hashes.
+var zobristKeys = newZobristKeys(rand.New(rand.NewSource(0x5eed)))
+
+type zobristKeyTable struct {
+	food   [zobristCells]uint64
+	hazard [zobristCells]uint64
+	head   [zobristSnakes][zobristCells]uint64
+	body   [zobristSnakes][zobristCells]uint64
+	tail   [zobristSnakes][zobristCells]uint64
+	health [zobristSnakes][maxHealth/healthBucketSize + 1]uint64
+	length [zobristSnakes][zobristLengths]uint64
+}
+
+func newZobristKeys(rnd *rand.Rand) *zobristKeyTable {
+	keys := &zobristKeyTable{}
+	for cell := 0; cell < zobristCells; cell++ {
+		keys.food[cell] = rnd.Uint64()
+		keys.hazard[cell] = rnd.Uint64()
+		for slot := 0; slot < zobristSnakes; slot++ {
+			keys.head[slot][cell] = rnd.Uint64()
+			keys.body[slot][cell] = rnd.Uint64()
+			keys.tail[slot][cell] = rnd.Uint64()
+		}
+	}
+	for slot := 0; slot < zobristSnakes; slot++ {
+		for bucket := range keys.health[slot] {
+			keys.health[slot][bucket] = rnd.Uint64()
+		}
+		for length := range keys.length[slot] {
+			keys.length[slot][length] = rnd.Uint64()
+		}
+	}
+	return keys
+}
+
+func zobristCell(coord Coord) int {
+	return (coord.X + coord.Y*32) % zobristCells
+}
+
+// zobristHash returns the Zobrist hash of the board. Snakes are told apart by
+// their position in Snakes, food and hazards only by their position on the
+// board. Health is only hashed in buckets of healthBucketSize.
+func (board Board) zobristHash() uint64 {
+	var hash uint64
+	for _, food := range board.Food {
+		hash ^= zobristKeys.food[zobristCell(food)]
+	}
+	for _, hazard := range board.Hazards {
+		hash ^= zobristKeys.hazard[zobristCell(hazard)]
+	}
+	for i, snake := range board.Snakes {
+		slot := i % zobristSnakes
+		segments := snake.segments()
+		hash ^= zobristKeys.head[slot][zobristCell(snake.Head)]
+		hash ^= zobristKeys.tail[slot][zobristCell(segments[len(segments)-1])]
+		for _, segment := range segments[1:] {
+			hash ^= zobristKeys.body[slot][zobristCell(segment)]
+		}
+		health := snake.Health
+		if health < 0 {
+			health = 0
+		}
+		if health > maxHealth {
+			health = maxHealth
+		}
+		hash ^= zobristKeys.health[slot][health/healthBucketSize]
+		hash ^= zobristKeys.length[slot][len(segments)%zobristLengths]
+	}
+	return hash
+}
+
+// Kinds of scores stored in a TranspositionTable. Searches with alpha-beta
+// pruning often only learn bounds of a score.
+const (
+	exactScore = iota
+	lowerBound
+	upperBound
+)
+
+type transposition struct {
+	key   uint64
+	depth int
+	kind  int
+	score float64
+	move  SnakeDirectionType
+	// generation is the search the entry was stored in.
+	generation int
+}
+
+// TranspositionTable remembers the results of searches by the Zobrist hash of
+// the searched board, so that positions reached in different ways, at
+// different depths or in consecutive turns are only searched once. Its size
+// is fixed; when two boards compete for the same slot, the result of the
+// deeper search is kept, unless it is left over from an earlier search. It is
+// safe for concurrent use.
+type TranspositionTable struct {
+	mu         sync.Mutex
+	entries    []transposition
+	generation int
+}
+
+// NewTranspositionTable creates a table with room for size results.
+func NewTranspositionTable(size int) *TranspositionTable {
+	if size < 1 {
+		size = 1
+	}
+	return &TranspositionTable{entries: make([]transposition, size)}
+}
+
+// newSearch starts a new generation of entries. Entries of earlier searches
+// stay usable, but make way for any entry of the new search.
+func (table *TranspositionTable) newSearch() {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	table.generation++
+}
+
+// lookup returns the entry of the board with the given key, with its score as
+// seen from a search with the given remaining depth.
+func (table *TranspositionTable) lookup(key uint64, depth int) (transposition, bool) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	entry := table.entries[key%uint64(len(table.entries))]
+	if entry.key != key || len(entry.move) == 0 {
+		return entry, false
+	}
+	entry.score = fromTableScore(entry.score, depth)
+	return entry, true
+}
+
+func (table *TranspositionTable) store(entry transposition) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	entry.generation = table.generation
+	entry.score = toTableScore(entry.score, entry.depth)
+	slot := &table.entries[entry.key%uint64(len(table.entries))]
+	if len(slot.move) == 0 || slot.generation != entry.generation || entry.depth >= slot.depth {
+		*slot = entry
+	}
+}
+
+// Scores of won, lost and drawn games contain the remaining depth at which
+// the game ended, added for wins and subtracted for losses and draws, so that
+// earlier wins and later losses score higher. The table stores them relative
+// to the board they belong to, as the board may be reached again with a
+// different remaining depth. Draws score half a loss, so anything below half
+// a draw is a loss or a draw.
+func toTableScore(score float64, depth int) float64 {
+	switch {
+	case score >= winScore/2:
+		return score - float64(depth)
+	case score <= drawScore/2:
+		return score + float64(depth)
+	}
+	return score
+}
+
+func fromTableScore(score float64, depth int) float64 {
+	switch {
+	case score >= winScore/2:
+		return score + float64(depth)
+	case score <= drawScore/2:
+		return score - float64(depth)
+	}
+	return score
+}
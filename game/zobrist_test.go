@@ -0,0 +1,139 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestZobristHash(t *testing.T) {
+	snake := Battlesnake{ID: "1", Health: 90, Head: Coord{X: 5, Y: 5}, Body: []Coord{{X: 5, Y: 4}, {X: 5, Y: 3}}, Length: 3}
+	opponent := Battlesnake{ID: "2", Health: 80, Head: Coord{X: 2, Y: 2}, Body: []Coord{{X: 2, Y: 3}, {X: 2, Y: 4}}, Length: 3}
+	board := Board{Height: 11, Width: 11, Food: []Coord{{X: 0, Y: 0}, {X: 9, Y: 9}}, Snakes: []Battlesnake{snake, opponent}}
+	hash := board.zobristHash()
+
+	same := board.clone()
+	same.Food = []Coord{{X: 9, Y: 9}, {X: 0, Y: 0}}
+	same.Snakes[0].Health = 95
+	same.Snakes[1].Body = append([]Coord{opponent.Head}, opponent.Body...)
+
+	if same.zobristHash() != hash {
+		t.Errorf("Expected same hash for same board")
+	}
+
+	tests := []struct {
+		Name   string
+		Change func(board *Board)
+	}{
+		{"Expect different hash after eating", func(board *Board) { board.Food = board.Food[1:] }},
+		{"Expect different hash in other health bucket", func(board *Board) { board.Snakes[0].Health = 50 }},
+		{"Expect different hash after moving", func(board *Board) {
+			*board, _ = Rules{}.NextBoard(*board, map[string]SnakeDirectionType{"1": SnakeDirection.UP, "2": SnakeDirection.LEFT})
+		}},
+		{"Expect different hash with swapped snakes", func(board *Board) {
+			board.Snakes[0], board.Snakes[1] = board.Snakes[1], board.Snakes[0]
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			changed := board.clone()
+			tt.Change(&changed)
+
+			if changed.zobristHash() == hash {
+				t.Errorf("Expected hash to change")
+			}
+		})
+	}
+}
+
+func TestTranspositionTableKeepsDeeperResult(t *testing.T) {
+	table := NewTranspositionTable(4)
+
+	table.store(transposition{key: 1, depth: 3, move: SnakeDirection.UP})
+	table.store(transposition{key: 1, depth: 2, move: SnakeDirection.DOWN})
+	if entry, ok := table.lookup(1, 3); !ok || entry.move != SnakeDirection.UP {
+		t.Errorf("Expected deeper result to be kept, got %v", entry)
+	}
+
+	// Keys 1 and 5 compete for the same slot.
+	table.store(transposition{key: 5, depth: 1, move: SnakeDirection.LEFT})
+	if entry, ok := table.lookup(1, 3); !ok || entry.move != SnakeDirection.UP {
+		t.Errorf("Expected deeper result to be kept over shallower one of other board, got %v", entry)
+	}
+	if _, ok := table.lookup(5, 1); ok {
+		t.Errorf("Expected shallower result of other board to be dropped")
+	}
+
+	table.store(transposition{key: 5, depth: 4, move: SnakeDirection.RIGHT})
+	if entry, ok := table.lookup(5, 4); !ok || entry.move != SnakeDirection.RIGHT {
+		t.Errorf("Expected deeper result of other board to replace it, got %v", entry)
+	}
+
+	table.newSearch()
+	table.store(transposition{key: 1, depth: 1, move: SnakeDirection.LEFT})
+	if entry, ok := table.lookup(1, 1); !ok || entry.move != SnakeDirection.LEFT {
+		t.Errorf("Expected result of earlier search to make way, got %v", entry)
+	}
+}
+
+func TestTranspositionTableAdjustsDecisiveScores(t *testing.T) {
+	table := NewTranspositionTable(4)
+
+	// Games ending two moves ahead of a board searched with 5 moves
+	// remaining score like games ending with 3 moves remaining.
+	tests := []struct {
+		Name     string
+		Stored   float64
+		Expected float64
+	}{
+		{"Expect win two moves ahead to be found at depth 7", winScore + 3, winScore + 5},
+		{"Expect loss two moves ahead to be found at depth 7", lossScore - 3, lossScore - 5},
+		{"Expect draw two moves ahead to be found at depth 7", drawScore - 3, drawScore - 5},
+		{"Expect evaluation to be kept", 42, 42},
+	}
+
+	for i, tt := range tests {
+		key := uint64(i)
+		table.store(transposition{key: key, depth: 5, kind: exactScore, score: tt.Stored, move: SnakeDirection.UP})
+
+		if entry, _ := table.lookup(key, 7); entry.score != tt.Expected {
+			t.Errorf("%s: expected %v, got %v", tt.Name, tt.Expected, entry.score)
+		}
+	}
+}
+
+func TestMinimaxWithTranspositionTable(t *testing.T) {
+	snake, board := createPocketBoard()
+	table := NewTranspositionTable(defaultTableSize)
+
+	for turn := 0; turn < 2; turn++ {
+		move := Minimax{Depth: 3, Table: table}.Execute(context.Background(), snake, board)
+
+		if move != SnakeDirection.RIGHT {
+			t.Errorf("Expected to avoid dead end (%s) in search %d, got %s", SnakeDirection.RIGHT, turn+1, move)
+		}
+	}
+	if _, ok := table.lookup(board.zobristHash(), 3); !ok {
+		t.Errorf("Expected result of root to be stored")
+	}
+}
+
+func TestMinimaxDeepensUntilDeadline(t *testing.T) {
+	snake, board := createPocketBoard()
+	strategic := &StrategicBattlesnake{
+		Snake:    snake,
+		Strategy: Minimax{Depth: 1, Table: NewTranspositionTable(defaultTableSize)},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	move, err := strategic.NextMove(ctx, board)
+
+	if err != nil {
+		t.Errorf("Expected search to end before deadline, got %v", err)
+	}
+	if move != SnakeDirection.RIGHT {
+		t.Errorf("Expected to avoid dead end (%s), got %s", SnakeDirection.RIGHT, move)
+	}
+}
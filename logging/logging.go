@@ -0,0 +1,240 @@
+// Package logging writes leveled log lines with fields, as text or JSON, so
+// that the logs of concurrent games can be told apart and processed by other
+// tools. Loggers travel with the context of a request.
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log line.
+type Level int
+
+const (
+	// LevelDebug is for details that help to follow single moves.
+	LevelDebug Level = iota
+	// LevelInfo is for the course of games, like their start and end.
+	LevelInfo
+	// LevelWarn is for problems the server recovers from, like fallback
+	// moves and invalid requests.
+	LevelWarn
+	// LevelError is for failures, like panicking handlers or the server
+	// stopping.
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (level Level) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int(level))
+}
+
+// ParseLevel returns the level with the given name, e.g. "debug".
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(name)
+	if name == "warning" {
+		return LevelWarn, nil
+	}
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Formats of log lines.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Names of the fields shared by the log lines of a game.
+const (
+	FieldGame  = "game"
+	FieldTurn  = "turn"
+	FieldSnake = "snake"
+	// FieldLatency is the time it took to answer a request in milliseconds.
+	FieldLatency = "latency_ms"
+)
+
+type field struct {
+	key   string
+	value interface{}
+}
+
+// output is shared by a logger and the loggers derived from it, so that their
+// lines don't interleave.
+type output struct {
+	mu     sync.Mutex
+	writer io.Writer
+	now    func() time.Time
+}
+
+// Logger writes log lines of its level or above, with its fields. Loggers are
+// safe for concurrent use.
+type Logger struct {
+	output *output
+	level  Level
+	json   bool
+	fields []field
+}
+
+// New returns a logger that writes lines of the given level and above in the
+// given format to w.
+func New(w io.Writer, level Level, format string) *Logger {
+	return &Logger{
+		output: &output{writer: w, now: time.Now},
+		level:  level,
+		json:   format == FormatJSON,
+	}
+}
+
+// Default writes to standard error. Its level is set by the LOG_LEVEL
+// environment variable (info by default) and its format by LOG_FORMAT (text
+// by default).
+var Default = FromEnv("LOG_LEVEL", "LOG_FORMAT")
+
+// FromEnv returns a logger that writes to standard error with the level and
+// format named by the given environment variables.
+func FromEnv(levelName string, formatName string) *Logger {
+	logger := New(os.Stderr, LevelInfo, FormatText)
+	if value := os.Getenv(levelName); len(value) > 0 {
+		level, err := ParseLevel(value)
+		if err != nil {
+			logger.Warn("Ignoring invalid %s %q", levelName, value)
+		} else {
+			logger.level = level
+		}
+	}
+	switch value := os.Getenv(formatName); value {
+	case "", FormatText:
+	case FormatJSON:
+		logger.json = true
+	default:
+		logger.Warn("Ignoring invalid %s %q", formatName, value)
+	}
+	return logger
+}
+
+// With returns a logger that adds the field to all lines.
+func (logger *Logger) With(key string, value interface{}) *Logger {
+	derived := *logger
+	derived.fields = append(append([]field(nil), logger.fields...), field{key, value})
+	return &derived
+}
+
+// Enabled tells whether lines of the level are written.
+func (logger *Logger) Enabled(level Level) bool {
+	return level >= logger.level
+}
+
+// Debug writes a line of LevelDebug, formatted like fmt.Sprintf.
+func (logger *Logger) Debug(format string, args ...interface{}) {
+	logger.log(LevelDebug, format, args...)
+}
+
+// Info writes a line of LevelInfo, formatted like fmt.Sprintf.
+func (logger *Logger) Info(format string, args ...interface{}) {
+	logger.log(LevelInfo, format, args...)
+}
+
+// Warn writes a line of LevelWarn, formatted like fmt.Sprintf.
+func (logger *Logger) Warn(format string, args ...interface{}) {
+	logger.log(LevelWarn, format, args...)
+}
+
+// Error writes a line of LevelError, formatted like fmt.Sprintf.
+func (logger *Logger) Error(format string, args ...interface{}) {
+	logger.log(LevelError, format, args...)
+}
+
+func (logger *Logger) log(level Level, format string, args ...interface{}) {
+	if !logger.Enabled(level) {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+
+	logger.output.mu.Lock()
+	defer logger.output.mu.Unlock()
+	now := logger.output.now()
+	var line []byte
+	if logger.json {
+		line = logger.formatJSON(now, level, message)
+	} else {
+		line = logger.formatText(now, level, message)
+	}
+	logger.output.writer.Write(line)
+}
+
+// formatText writes lines like
+//
+//	2021-03-20T12:00:00.000Z INFO Game started game=abc turn=0
+func (logger *Logger) formatText(now time.Time, level Level, message string) []byte {
+	var line strings.Builder
+	line.WriteString(now.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	line.WriteString(" " + strings.ToUpper(level.String()) + " " + message)
+	for _, field := range logger.fields {
+		value := fmt.Sprint(field.value)
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		line.WriteString(" " + field.key + "=" + value)
+	}
+	line.WriteString("\n")
+	return []byte(line.String())
+}
+
+// formatJSON writes lines like
+//
+//	{"game":"abc","level":"info","msg":"Game started","time":"...","turn":0}
+func (logger *Logger) formatJSON(now time.Time, level Level, message string) []byte {
+	values := map[string]interface{}{}
+	for _, field := range logger.fields {
+		values[field.key] = field.value
+	}
+	values["time"] = now.UTC().Format(time.RFC3339Nano)
+	values["level"] = level.String()
+	values["msg"] = message
+
+	line, err := json.Marshal(values)
+	if err != nil {
+		// Fall back to the text of values that can't be marshalled.
+		for key, value := range values {
+			values[key] = fmt.Sprint(value)
+		}
+		line, _ = json.Marshal(values)
+	}
+	return append(line, '\n')
+}
+
+type contextKey struct{}
+
+// NewContext returns a context that carries the logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the context, or Default if it has none.
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return logger
+	}
+	return Default
+}
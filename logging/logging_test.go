@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestLogger(level Level, format string) (*Logger, *bytes.Buffer) {
+	var buffer bytes.Buffer
+	logger := New(&buffer, level, format)
+	logger.output.now = func() time.Time {
+		return time.Date(2021, 3, 20, 12, 0, 0, 0, time.UTC)
+	}
+	return logger, &buffer
+}
+
+func TestTextFormat(t *testing.T) {
+	logger, buffer := newTestLogger(LevelInfo, FormatText)
+
+	logger.With(FieldGame, "abc").With(FieldTurn, 3).With("action", "Avoid Dead Ends").Info("Moved %s", "up")
+
+	expected := "2021-03-20T12:00:00.000Z INFO Moved up game=abc turn=3 action=\"Avoid Dead Ends\"\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	logger, buffer := newTestLogger(LevelInfo, FormatJSON)
+
+	logger.With(FieldGame, "abc").With(FieldLatency, 12.5).Warn("Falling back")
+
+	expected := `{"game":"abc","latency_ms":12.5,"level":"warn","msg":"Falling back","time":"2021-03-20T12:00:00Z"}` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func TestJSONFormatOfUnmarshallableValues(t *testing.T) {
+	logger, buffer := newTestLogger(LevelInfo, FormatJSON)
+
+	logger.With("channel", make(chan int)).Info("Odd value")
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &values); err != nil || values["msg"] != "Odd value" {
+		t.Errorf("Expected valid JSON, got %q (%v)", buffer.String(), err)
+	}
+}
+
+func TestLevels(t *testing.T) {
+	logger, buffer := newTestLogger(LevelWarn, FormatText)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	expected := "2021-03-20T12:00:00.000Z WARN warn\n2021-03-20T12:00:00.000Z ERROR error\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func TestWithKeepsParentFields(t *testing.T) {
+	logger, buffer := newTestLogger(LevelInfo, FormatText)
+	game := logger.With(FieldGame, "abc")
+
+	game.With(FieldTurn, 1).Info("first")
+	game.With(FieldSnake, "me").Info("second")
+
+	expected := "2021-03-20T12:00:00.000Z INFO first game=abc turn=1\n2021-03-20T12:00:00.000Z INFO second game=abc snake=me\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Expected Level
+		Valid    bool
+	}{
+		{"debug", LevelDebug, true},
+		{"INFO", LevelInfo, true},
+		{"warning", LevelWarn, true},
+		{"error", LevelError, true},
+		{"verbose", LevelInfo, false},
+	}
+
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.Name)
+		if level != tt.Expected || (err == nil) != tt.Valid {
+			t.Errorf("Expected %v (valid: %v) for %q, got %v (%v)", tt.Expected, tt.Valid, tt.Name, level, err)
+		}
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	tests := []struct {
+		Level    Level
+		Expected string
+	}{
+		{LevelDebug, "debug"},
+		{LevelError, "error"},
+		{Level(7), "Level(7)"},
+	}
+
+	for _, tt := range tests {
+		if tt.Level.String() != tt.Expected {
+			t.Errorf("Expected %q, got %q", tt.Expected, tt.Level.String())
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	defer os.Unsetenv("TEST_LOG_LEVEL")
+	defer os.Unsetenv("TEST_LOG_FORMAT")
+	os.Setenv("TEST_LOG_LEVEL", "debug")
+	os.Setenv("TEST_LOG_FORMAT", "json")
+
+	logger := FromEnv("TEST_LOG_LEVEL", "TEST_LOG_FORMAT")
+
+	if !logger.Enabled(LevelDebug) || !logger.json {
+		t.Errorf("Expected a debug logger writing JSON, got %+v", logger)
+	}
+}
+
+func TestContext(t *testing.T) {
+	logger, _ := newTestLogger(LevelInfo, FormatText)
+
+	if FromContext(context.Background()) != Default {
+		t.Errorf("Expected the default logger without a logger in the context")
+	}
+	if FromContext(NewContext(context.Background(), logger)) != logger {
+		t.Errorf("Expected the logger of the context")
+	}
+}
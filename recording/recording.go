@@ -0,0 +1,120 @@
+// Package recording writes the requests the server receives and its answers
+// to one JSONL file per game, so that games can be analyzed and replayed
+// later.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Types of entries, named after the routes of the requests.
+const (
+	TypeStart = "start"
+	TypeMove  = "move"
+	TypeEnd   = "end"
+)
+
+// Entry is one line of a recording.
+type Entry struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Request is the body of the request as sent by the engine.
+	Request json.RawMessage `json:"request"`
+	// Response is the body of our response, if there was one.
+	Response json.RawMessage `json:"response,omitempty"`
+	Strategy string          `json:"strategy,omitempty"`
+	Action   string          `json:"action,omitempty"`
+	// ComputeMillis is the time it took to compute the response.
+	ComputeMillis float64 `json:"compute_ms,omitempty"`
+	// Error describes why a fallback move was made, if one was.
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder appends entries to the recording of their game. It is safe for
+// concurrent use.
+type Recorder struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewRecorder creates a Recorder that writes its files into dir, creating the
+// directory if necessary.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir}, nil
+}
+
+var unsafeFileNameCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Path returns the path of the recording of the game with the given ID. IDs
+// with characters that are not safe in file names get a hash of the ID
+// appended to the name, so that IDs like "a/b" and "a_b" don't share a file.
+func (recorder *Recorder) Path(gameID string) string {
+	name := unsafeFileNameCharacters.ReplaceAllString(gameID, "_")
+	if name != gameID {
+		hash := fnv.New32a()
+		hash.Write([]byte(gameID))
+		name = fmt.Sprintf("%s.%08x", name, hash.Sum32())
+	}
+	return filepath.Join(recorder.dir, name+".jsonl")
+}
+
+// Record appends the entry to the recording of the game with the given ID.
+func (recorder *Recorder) Record(gameID string, entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	file, err := os.OpenFile(recorder.Path(gameID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Read returns all entries of a recording.
+func Read(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ReadFile returns all entries of the recording in the file.
+func ReadFile(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
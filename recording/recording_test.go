@@ -0,0 +1,83 @@
+package recording
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	recorder, err := NewRecorder(filepath.Join(dir, "games"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := recorder.Record("game-1", Entry{Type: TypeMove, Request: json.RawMessage(`{"turn": 1}`), Response: json.RawMessage(`{"move": "up"}`)})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	recorder.Record("game-2", Entry{Type: TypeStart, Request: json.RawMessage(`{}`)})
+
+	entries, err := ReadFile(recorder.Path("game-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 10 {
+		t.Fatalf("Expected 10 entries, got %d", len(entries))
+	}
+	if entries[0].Type != TypeMove || string(entries[0].Response) != `{"move":"up"}` {
+		t.Errorf("Expected recorded move, got %+v", entries[0])
+	}
+	if entries, _ := ReadFile(recorder.Path("game-2")); len(entries) != 1 {
+		t.Errorf("Expected separate recording per game, got %d entries", len(entries))
+	}
+}
+
+func TestPathsOfDifferentGamesDiffer(t *testing.T) {
+	recorder := &Recorder{dir: "games"}
+
+	tests := []struct {
+		First  string
+		Second string
+	}{
+		{"a/b", "a_b"},
+		{"a/b", "a:b"},
+		{"a b", "a_b"},
+	}
+
+	for _, tt := range tests {
+		if recorder.Path(tt.First) == recorder.Path(tt.Second) {
+			t.Errorf("Expected %q and %q to be recorded in different files, got %s", tt.First, tt.Second, recorder.Path(tt.First))
+		}
+	}
+	if path := recorder.Path("game-1"); path != filepath.Join("games", "game-1.jsonl") {
+		t.Errorf("Expected safe IDs to be kept, got %s", path)
+	}
+}
+
+func TestPathIsKeptInDirectory(t *testing.T) {
+	recorder := &Recorder{dir: "games"}
+
+	path := recorder.Path("../../etc/passwd")
+
+	if filepath.Dir(path) != "games" || strings.Contains(path, "..") {
+		t.Errorf("Expected recording to stay in its directory, got %s", path)
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/flutter-clutter/starter-snake-go/api"
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+// maxRequestBytes limits the size of request bodies we are willing to decode.
+const maxRequestBytes = 1 << 20
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// decodeGameRequest reads and validates the GameRequest sent with r. The body
+// of the request is returned as well, e.g. to record it.
+func decodeGameRequest(w http.ResponseWriter, r *http.Request) (GameRequest, []byte, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		return GameRequest{}, body, fmt.Errorf("invalid game request: %w", err)
+	}
+	request, err := api.DecodeGameRequest(body)
+	return request, body, err
+}
+
+// writeJSON sends response as JSON. Failures can only be logged, as the
+// client may already have received parts of the response.
+func writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		logging.Default.Warn("Could not write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	logging.Default.Warn("Answering with %d: %v", status, err)
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+// recoverPanics keeps a panicking handler from taking down the whole server
+// and answers with an internal server error instead.
+func recoverPanics(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logging.FromContext(r.Context()).Error("Recovered from panic in %s: %v\n%s", r.URL.Path, recovered, debug.Stack())
+				writeError(w, http.StatusInternalServerError, errors.New("internal server error"))
+			}
+		}()
+		handler.ServeHTTP(w, r)
+	})
+}
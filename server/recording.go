@@ -0,0 +1,38 @@
+package server
+
+import (
+	"os"
+	"time"
+
+	"github.com/flutter-clutter/starter-snake-go/logging"
+	"github.com/flutter-clutter/starter-snake-go/recording"
+)
+
+// recorder writes every game into the directory named by the RECORD_DIR
+// environment variable. Games are not recorded if it is nil.
+var recorder = recorderFromEnv("RECORD_DIR")
+
+func recorderFromEnv(name string) *recording.Recorder {
+	dir := os.Getenv(name)
+	if len(dir) == 0 {
+		return nil
+	}
+	recorder, err := recording.NewRecorder(dir)
+	if err != nil {
+		logging.Default.Warn("Not recording games: %v", err)
+		return nil
+	}
+	return recorder
+}
+
+// record adds the entry to the recording of the request's game, if games are
+// recorded. Failures are only logged, as they must not cost us the game.
+func record(request GameRequest, entry recording.Entry) {
+	if recorder == nil {
+		return
+	}
+	entry.Time = time.Now()
+	if err := recorder.Record(request.Game.ID, entry); err != nil {
+		requestLogger(request).Warn("Could not record game: %v", err)
+	}
+}
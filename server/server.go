@@ -1,42 +1,61 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/flutter-clutter/starter-snake-go/api"
 	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/logging"
+	"github.com/flutter-clutter/starter-snake-go/recording"
 )
 
-var snake *game.StrategicBattlesnake
+var sessions = newSessionStore(sessionTTL)
 
-type Game struct {
-	ID      string `json:"id"`
-	Timeout int32  `json:"timeout"`
-}
+// latencyMargin is the part of a game's timeout that is reserved for sending
+// the move back to the engine. It can be set in milliseconds with the
+// LATENCY_MARGIN_MS environment variable.
+var latencyMargin = durationFromEnv("LATENCY_MARGIN_MS", 150*time.Millisecond)
 
-type BattlesnakeInfoResponse struct {
-	APIVersion string `json:"apiversion"`
-	Author     string `json:"author"`
-	Color      string `json:"color"`
-	Head       string `json:"head"`
-	Tail       string `json:"tail"`
-}
+// traceMoves tells whether the decision for every move is logged and sent in
+// the traceHeader of the response. It is set with the TRACE environment
+// variable.
+var traceMoves = boolFromEnv("TRACE")
 
-type GameRequest struct {
-	Game  Game             `json:"game"`
-	Turn  int              `json:"turn"`
-	Board game.Board       `json:"board"`
-	You   game.Battlesnake `json:"you"`
-}
+const (
+	// traceHeader carries the events of the decision for a move as JSON.
+	traceHeader = "X-Decision-Trace"
+	// droppedTraceHeader counts the events left out of the traceHeader to
+	// keep it within maxTraceHeaderBytes.
+	droppedTraceHeader = "X-Decision-Trace-Dropped"
+	// maxTraceHeaderBytes keeps the traceHeader well below the header size
+	// limits of common proxies, which start at 8 KiB for all headers.
+	maxTraceHeaderBytes = 4096
+)
 
-type MoveResponse struct {
-	Move  game.SnakeDirectionType `json:"move"`
-	Shout string                  `json:"shout,omitempty"`
-}
+const (
+	// defaultTimeout is used if a game does not specify a timeout.
+	defaultTimeout = 500 * time.Millisecond
+	// minimumMoveBudget is the least amount of time given to a strategy,
+	// even if the latency margin exceeds the timeout.
+	minimumMoveBudget = 10 * time.Millisecond
+)
+
+// The requests and responses of the Battlesnake API are defined in package
+// api, so that other tools can use them without the configuration of the
+// server.
+type (
+	Game                    = api.Game
+	BattlesnakeInfoResponse = api.BattlesnakeInfoResponse
+	GameRequest             = api.GameRequest
+	MoveResponse            = api.MoveResponse
+)
 
 // HandleIndex is called when your Battlesnake is created and refreshed
 // by play.battlesnake.com. BattlesnakeInfoResponse contains information about
@@ -50,76 +69,208 @@ func HandleIndex(w http.ResponseWriter, r *http.Request) {
 		Tail:       "mouse",
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err := json.NewEncoder(w).Encode(response)
-	if err != nil {
-		log.Fatal(err)
-	}
+	writeJSON(w, http.StatusOK, response)
 }
 
 // HandleStart is called at the start of each game your Battlesnake is playing.
 // The GameRequest object contains information about the game that's about to start.
 // TODO: Use this function to decide how your Battlesnake is going to look on the board.
 func HandleStart(w http.ResponseWriter, r *http.Request) {
-	request := GameRequest{}
-	err := json.NewDecoder(r.Body).Decode(&request)
+	request, body, err := decodeGameRequest(w, r)
 	if err != nil {
-		log.Fatal(err)
+		writeError(w, http.StatusBadRequest, err)
+		return
 	}
 
-	if len(request.Board.Snakes) == 1 {
-		println("I am the only snake here")
-	} else {
-		println("Oh, other snakes here, too.")
-	}
-
-	snake = &game.StrategicBattlesnake{
-		Snake:    request.You,
-		Action:   game.ApproachBorder{},
-		Strategy: game.CircleInnerBorder{},
-	}
+	session := sessions.start(request)
 
 	w.WriteHeader(http.StatusOK)
-	fmt.Print("START\n")
+	requestLogger(request).With("snakes", len(request.Board.Snakes)).Info("Game started")
+
+	record(request, recording.Entry{
+		Type:     recording.TypeStart,
+		Request:  body,
+		Strategy: game.StrategyName(session.snake.Strategy),
+	})
 }
 
 // HandleMove is called for each turn of each game.
 // Valid responses are "up", "down", "left", or "right".
 // TODO: Use the information in the GameRequest object to determine your next move.
 func HandleMove(w http.ResponseWriter, r *http.Request) {
-	request := GameRequest{}
-	err := json.NewDecoder(r.Body).Decode(&request)
+	request, body, err := decodeGameRequest(w, r)
 	if err != nil {
-		log.Fatal(err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	session := sessions.get(request)
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	logger := requestLogger(request)
+	ctx, cancel := context.WithTimeout(logging.NewContext(r.Context(), logger), moveBudget(request.Game, latencyMargin))
+	defer cancel()
+	var trace *game.Trace
+	if traceMoves {
+		ctx, trace = game.WithTrace(ctx)
 	}
 
+	snake := session.snake
 	snake.Snake = request.You
-	snake.Action = snake.Strategy.ExecuteNextStep(snake.Snake, request.Board)
+	start := time.Now()
+	move, err := snake.NextMove(ctx, request.Board)
+	computeTime := time.Since(start)
+	entry := recording.Entry{
+		Type:          recording.TypeMove,
+		Request:       body,
+		Strategy:      game.StrategyName(snake.Strategy),
+		ComputeMillis: float64(computeTime) / float64(time.Millisecond),
+	}
+	logger = logger.With(logging.FieldLatency, entry.ComputeMillis)
+	if trace != nil {
+		logger = logger.With("trace", trace)
+	}
+	// Every move is logged once, at debug level unless its decision is
+	// traced, which asks for it to be logged.
+	if err != nil {
+		logger.Warn("Falling back to %s: %v", move, err)
+		entry.Error = err.Error()
+	} else {
+		entry.Action = game.ActionName(snake.Action)
+		if trace != nil {
+			logger.Info("Moving %s by %s", move, entry.Action)
+		} else {
+			logger.Debug("Moving %s by %s", move, entry.Action)
+		}
+	}
 
 	response := MoveResponse{
-		Move: snake.Action.Execute(request.You, request.Board),
+		Move: move,
+	}
+
+	if trace != nil {
+		encoded, dropped := encodeTrace(trace.Events(), maxTraceHeaderBytes)
+		w.Header().Set(traceHeader, encoded)
+		if dropped > 0 {
+			w.Header().Set(droppedTraceHeader, strconv.Itoa(dropped))
+		}
 	}
 
-	//fmt.Printf("MOVE: %s\n", response.Move)
+	writeJSON(w, http.StatusOK, response)
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(response)
+	action := entry.Action
 	if err != nil {
-		log.Fatal(err)
+		action = "fallback"
 	}
+	spectate(request, move, action, computeTime)
+
+	entry.Response, _ = json.Marshal(response)
+	record(request, entry)
 }
 
 // HandleEnd is called when a game your Battlesnake was playing has ended.
 // It's purely for informational purposes, no response required.
 func HandleEnd(w http.ResponseWriter, r *http.Request) {
-	request := GameRequest{}
-	err := json.NewDecoder(r.Body).Decode(&request)
+	request, body, err := decodeGameRequest(w, r)
 	if err != nil {
-		log.Fatal(err)
+		writeError(w, http.StatusBadRequest, err)
+		return
 	}
 
+	sessions.end(request)
+	endSpectating(request)
+
 	// Nothing to respond with here
-	fmt.Print("END\n")
+	requestLogger(request).Info("Game ended")
+
+	record(request, recording.Entry{Type: recording.TypeEnd, Request: body})
+}
+
+// encodeTrace returns the events as JSON of at most limit bytes. The earliest
+// events are dropped if necessary, as the final decision comes last. The
+// number of dropped events is returned as well.
+func encodeTrace(events []game.TraceEvent, limit int) (string, int) {
+	encoded := make([][]byte, len(events))
+	for i, event := range events {
+		var err error
+		if encoded[i], err = json.Marshal(event); err != nil {
+			return "[]", len(events)
+		}
+	}
+
+	// Keep the longest suffix that fits between the brackets, with commas
+	// between the events.
+	first, size := len(events), len("[]")
+	for first > 0 {
+		next := size + len(encoded[first-1])
+		if first < len(events) {
+			next++
+		}
+		if next > limit {
+			break
+		}
+		first, size = first-1, next
+	}
+
+	var trace bytes.Buffer
+	trace.WriteByte('[')
+	for i, event := range encoded[first:] {
+		if i > 0 {
+			trace.WriteByte(',')
+		}
+		trace.Write(event)
+	}
+	trace.WriteByte(']')
+	return trace.String(), first
+}
+
+// moveBudget returns the time the strategy may take to compute a move in the
+// given game.
+func moveBudget(game Game, margin time.Duration) time.Duration {
+	timeout := time.Duration(game.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	budget := timeout - margin
+	if budget < minimumMoveBudget {
+		return minimumMoveBudget
+	}
+	return budget
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return fallback
+	}
+	milliseconds, err := strconv.Atoi(value)
+	if err != nil || milliseconds < 0 {
+		logging.Default.Warn("Ignoring invalid %s %q", name, value)
+		return fallback
+	}
+	return time.Duration(milliseconds) * time.Millisecond
+}
+
+// requestLogger returns a logger with the fields of the request's game.
+func requestLogger(request GameRequest) *logging.Logger {
+	return logging.Default.
+		With(logging.FieldGame, request.Game.ID).
+		With(logging.FieldTurn, request.Turn).
+		With(logging.FieldSnake, request.You.ID)
+}
+
+func boolFromEnv(name string) bool {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logging.Default.Warn("Ignoring invalid %s %q", name, value)
+		return false
+	}
+	return enabled
 }
 
 func Start() {
@@ -136,9 +287,11 @@ func Start() {
 		MaxHeaderBytes: 1 << 20,
 	}
 
-	fmt.Printf("Starting Battlesnake Server at http://0.0.0.0:%s...\n", port)
+	logging.Default.Info("Starting Battlesnake Server at http://0.0.0.0:%s...", port)
 
-	log.Fatal(s.ListenAndServe())
+	err := s.ListenAndServe()
+	logging.Default.Error("Server stopped: %v", err)
+	os.Exit(1)
 }
 
 func setupRouter() http.Handler {
@@ -148,5 +301,5 @@ func setupRouter() http.Handler {
 	handler.HandleFunc("/move", HandleMove)
 	handler.HandleFunc("/end", HandleEnd)
 
-	return handler
+	return recoverPanics(handler)
 }
@@ -2,14 +2,22 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/logging"
+	"github.com/flutter-clutter/starter-snake-go/recording"
 )
 
 func TestIndexReturnsCorrectResponse(t *testing.T) {
@@ -74,10 +82,285 @@ func TestMoveReturnsCorrectResponse(t *testing.T) {
 	}
 }
 
+func TestSessionsAreKeptPerGame(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	first := createGameRequest()
+	first.Game.ID = "session-test-1"
+	second := createGameRequest()
+	second.Game.ID = "session-test-2"
+
+	before := sessions.len()
+	sendGameRequest(t, first, server.URL, "start").Body.Close()
+	sendGameRequest(t, second, server.URL, "start").Body.Close()
+
+	if sessions.len() != before+2 {
+		t.Errorf("Expected %d sessions, got %d", before+2, sessions.len())
+		return
+	}
+
+	sendGameRequest(t, first, server.URL, "end").Body.Close()
+
+	if sessions.len() != before+1 {
+		t.Errorf("Expected %d sessions after end, got %d", before+1, sessions.len())
+		return
+	}
+}
+
+func TestSessionStoreExpiresAbandonedSessions(t *testing.T) {
+	now := time.Now()
+	store := newSessionStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	abandoned := createGameRequest()
+	abandoned.Game.ID = "abandoned"
+	store.start(abandoned)
+
+	now = now.Add(30 * time.Second)
+	active := createGameRequest()
+	active.Game.ID = "active"
+	store.start(active)
+
+	now = now.Add(45 * time.Second)
+	if removed := store.expire(); removed != 1 {
+		t.Errorf("Expected 1 expired session, got %d", removed)
+		return
+	}
+
+	if store.get(active).snake == nil {
+		t.Errorf("Expected active session to be kept")
+		return
+	}
+}
+
+func TestSessionStoreExpiresAbandonedSessionsOnGet(t *testing.T) {
+	now := time.Now()
+	store := newSessionStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	abandoned := createGameRequest()
+	abandoned.Game.ID = "abandoned"
+	store.start(abandoned)
+	active := createGameRequest()
+	active.Game.ID = "active"
+	store.start(active)
+
+	now = now.Add(2 * time.Minute)
+	store.get(active)
+
+	if store.len() != 1 {
+		t.Errorf("Expected only the active session to be kept, got %d sessions", store.len())
+	}
+}
+
+func TestMalformedRequestsAreRejected(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	tests := []struct {
+		Name  string
+		Route string
+		Body  string
+	}{
+		{
+			Name:  "Expect invalid JSON to be rejected on start",
+			Route: "start",
+			Body:  "{",
+		},
+		{
+			Name:  "Expect invalid JSON to be rejected on move",
+			Route: "move",
+			Body:  "not json",
+		},
+		{
+			Name:  "Expect request without game id to be rejected",
+			Route: "end",
+			Body:  `{"you": {"id": "1"}, "board": {"width": 11, "height": 11}}`,
+		},
+		{
+			Name:  "Expect request without board to be rejected",
+			Route: "move",
+			Body:  `{"game": {"id": "1"}, "you": {"id": "1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			resp, err := http.Post(fmt.Sprintf("%s/%s", server.URL, tt.Route), "application/json", strings.NewReader(tt.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected status code: 400. Got %d", resp.StatusCode)
+				return
+			}
+
+			var response ErrorResponse
+			err = json.NewDecoder(resp.Body).Decode(&response)
+			if err != nil || len(response.Error) == 0 {
+				t.Errorf("Expected JSON error body, got %v (%v)", response, err)
+			}
+		})
+	}
+}
+
+type panickingStrategy struct{}
+
+func (panickingStrategy) ExecuteNextStep(ctx context.Context, snake game.Battlesnake, board game.Board) game.Action {
+	panic("strategy is broken")
+}
+
+func TestMoveFallsBackWhenStrategyPanics(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	request := createGameRequest()
+	request.Game.ID = "panicking-strategy"
+	sendGameRequest(t, request, server.URL, "start").Body.Close()
+	sessions.get(request).snake.Strategy = panickingStrategy{}
+
+	resp := sendGameRequest(t, request, server.URL, "move")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected status code: 200. Got %d", resp.StatusCode)
+		return
+	}
+
+	var response MoveResponse
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Move != game.SnakeDirection.UP {
+		t.Errorf("Expected safe fallback move %s, got %s", game.SnakeDirection.UP, response.Move)
+	}
+}
+
+const officialMoveRequest = `{
+  "game": {
+    "id": "totally-unique-game-id",
+    "ruleset": {
+      "name": "royale",
+      "version": "v1.2.3",
+      "settings": {
+        "foodSpawnChance": 25,
+        "minimumFood": 1,
+        "hazardDamagePerTurn": 14,
+        "royale": {"shrinkEveryNTurns": 5},
+        "squad": {"allowBodyCollisions": true, "sharedElimination": true, "sharedHealth": true, "sharedLength": true}
+      }
+    },
+    "map": "standard",
+    "source": "league",
+    "timeout": 500
+  },
+  "turn": 14,
+  "board": {
+    "height": 11,
+    "width": 11,
+    "food": [{"x": 5, "y": 5}],
+    "hazards": [{"x": 0, "y": 0}, {"x": 0, "y": 1}],
+    "snakes": [{
+      "id": "snake-508e96ac-94ad-11ea-bb37",
+      "name": "My Snake",
+      "health": 54,
+      "body": [{"x": 0, "y": 0}, {"x": 1, "y": 0}, {"x": 2, "y": 0}],
+      "latency": "111",
+      "head": {"x": 0, "y": 0},
+      "length": 3,
+      "shout": "why are we shouting??",
+      "squad": "1",
+      "customizations": {"color": "#FF0000", "head": "pixel", "tail": "pixel"}
+    }]
+  },
+  "you": {
+    "id": "snake-508e96ac-94ad-11ea-bb37",
+    "name": "My Snake",
+    "health": 54,
+    "body": [{"x": 0, "y": 0}, {"x": 1, "y": 0}, {"x": 2, "y": 0}],
+    "latency": "111",
+    "head": {"x": 0, "y": 0},
+    "length": 3,
+    "shout": "why are we shouting??",
+    "squad": "1",
+    "customizations": {"color": "#FF0000", "head": "pixel", "tail": "pixel"}
+  }
+}`
+
+func TestDecodeOfficialGameRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/move", strings.NewReader(officialMoveRequest))
+
+	request, body, err := decodeGameRequest(httptest.NewRecorder(), r)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != officialMoveRequest {
+		t.Errorf("Expected body to be returned unchanged")
+	}
+	settings := request.Game.Ruleset.Settings
+	if request.Game.Ruleset.Name != game.RulesetRoyale || settings.HazardDamagePerTurn != 14 || settings.Royale.ShrinkEveryNTurns != 5 || !settings.Squad.SharedHealth {
+		t.Errorf("Ruleset was not decoded completely: %+v", request.Game.Ruleset)
+	}
+	if request.Game.Map != "standard" || request.Game.Source != "league" {
+		t.Errorf("Game was not decoded completely: %+v", request.Game)
+	}
+	if len(request.Board.Hazards) != 2 {
+		t.Errorf("Expected 2 hazards, got %v", request.Board.Hazards)
+	}
+	if request.Board.Ruleset.Name != game.RulesetRoyale {
+		t.Errorf("Expected ruleset to be available on the board, got %+v", request.Board.Ruleset)
+	}
+	if request.You.Latency != "111" || request.You.Squad != "1" || request.You.Customizations.Color != "#FF0000" {
+		t.Errorf("Snake was not decoded completely: %+v", request.You)
+	}
+}
+
+func TestMoveBudget(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Timeout  int32
+		Margin   time.Duration
+		Expected time.Duration
+	}{
+		{
+			Name:     "Expect margin to be subtracted from timeout",
+			Timeout:  500,
+			Margin:   150 * time.Millisecond,
+			Expected: 350 * time.Millisecond,
+		},
+		{
+			Name:     "Expect default timeout when game has none",
+			Timeout:  0,
+			Margin:   100 * time.Millisecond,
+			Expected: defaultTimeout - 100*time.Millisecond,
+		},
+		{
+			Name:     "Expect minimum budget when margin exceeds timeout",
+			Timeout:  60,
+			Margin:   150 * time.Millisecond,
+			Expected: minimumMoveBudget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			budget := moveBudget(Game{ID: "1", Timeout: tt.Timeout}, tt.Margin)
+			if budget != tt.Expected {
+				t.Errorf("Expected budget of %s, got %s", tt.Expected, budget)
+			}
+		})
+	}
+}
+
 func createGameRequest() GameRequest {
 	var snakeGame Game = Game{
-		"1",
-		int32(60),
+		ID:      "1",
+		Timeout: int32(60),
 	}
 
 	var snake game.Battlesnake = game.Battlesnake{
@@ -124,3 +407,265 @@ func sendGameRequest(t *testing.T, request GameRequest, url string, route string
 
 	return resp
 }
+
+func TestEvaluatorFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "weights")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	valid := filepath.Join(dir, "valid.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(valid, []byte(`{"area": 1, "health": 0.5}`), 0644)
+	ioutil.WriteFile(invalid, []byte(`{"luck": 1}`), 0644)
+	defer os.Unsetenv("TEST_WEIGHTS_FILE")
+
+	tests := []struct {
+		Name     string
+		Path     string
+		Expected bool
+	}{
+		{"Expect weights to be loaded", valid, true},
+		{"Expect default weights without file", "", false},
+		{"Expect default weights for invalid file", invalid, false},
+		{"Expect default weights for missing file", filepath.Join(dir, "missing.json"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			os.Setenv("TEST_WEIGHTS_FILE", tt.Path)
+
+			evaluator := evaluatorFromEnv("TEST_WEIGHTS_FILE")
+
+			if (evaluator != nil) != tt.Expected {
+				t.Errorf("Expected evaluator to be loaded: %v, got %v", tt.Expected, evaluator)
+			}
+		})
+	}
+}
+
+func TestGamesAreRecorded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recording")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	recorder, _ = recording.NewRecorder(dir)
+	defer func() { recorder = nil }()
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	request := createGameRequest()
+	request.Game.ID = "recorded-game"
+	for _, route := range []string{"start", "move", "end"} {
+		sendGameRequest(t, request, server.URL, route).Body.Close()
+	}
+
+	entries, err := recording.ReadFile(recorder.Path(request.Game.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected start, move and end to be recorded, got %d entries", len(entries))
+	}
+	move := entries[1]
+	if move.Type != recording.TypeMove || move.Strategy != "CircleInnerBorder" || len(move.Action) == 0 || len(move.Response) == 0 {
+		t.Errorf("Expected move with strategy, action and response, got %+v", move)
+	}
+	var recorded GameRequest
+	if err := json.Unmarshal(move.Request, &recorded); err != nil || recorded.Game.ID != request.Game.ID {
+		t.Errorf("Expected request to be recorded, got %s (%v)", move.Request, err)
+	}
+}
+
+func TestSpectatorSeesEveryMove(t *testing.T) {
+	var output bytes.Buffer
+	spectator = &spectatorView{out: &output}
+	defer func() { spectator = nil }()
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	request := createGameRequest()
+	request.Game.ID = "spectated-game"
+	request.Turn = 7
+	sendGameRequest(t, request, server.URL, "start").Body.Close()
+	sendGameRequest(t, request, server.URL, "move").Body.Close()
+
+	drawn := output.String()
+	if !strings.Contains(drawn, "Game spectated-game, turn 7:") || !strings.Contains(drawn, "(you)") {
+		t.Errorf("Expected the move and board to be drawn, got\n%s", drawn)
+	}
+	if !strings.Contains(drawn, " by AvoidDeadEnds(") {
+		t.Errorf("Expected the action to be named, got\n%s", drawn)
+	}
+}
+
+func TestSpectatorFollowsOneGame(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Filter   string
+		Expected []string
+	}{
+		{"Expect to follow the first game until it ends", "", []string{"game-1", "game-1", "game-2"}},
+		{"Expect to follow only the filtered game", "game-2", []string{"game-2", "game-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var output bytes.Buffer
+			spectator = &spectatorView{out: &output, filter: tt.Filter}
+			defer func() { spectator = nil }()
+			server := httptest.NewServer(setupRouter())
+			defer server.Close()
+
+			first, second := createGameRequest(), createGameRequest()
+			first.Game.ID, second.Game.ID = "game-1", "game-2"
+			for _, request := range []GameRequest{first, second} {
+				sendGameRequest(t, request, server.URL, "start").Body.Close()
+			}
+			for _, request := range []GameRequest{first, second, first} {
+				sendGameRequest(t, request, server.URL, "move").Body.Close()
+			}
+			sendGameRequest(t, first, server.URL, "end").Body.Close()
+			sendGameRequest(t, second, server.URL, "move").Body.Close()
+			sendGameRequest(t, second, server.URL, "end").Body.Close()
+
+			var drawn []string
+			for _, frame := range strings.Split(output.String(), clearScreen)[1:] {
+				drawn = append(drawn, strings.TrimSuffix(strings.Fields(frame)[1], ","))
+			}
+			if strings.Join(drawn, " ") != strings.Join(tt.Expected, " ") {
+				t.Errorf("Expected frames of %v, got %v", tt.Expected, drawn)
+			}
+		})
+	}
+}
+
+func TestDecisionTraceHeader(t *testing.T) {
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+	request := createGameRequest()
+	request.Game.ID = "traced-game"
+	sendGameRequest(t, request, server.URL, "start").Body.Close()
+
+	for _, enabled := range []bool{false, true} {
+		traceMoves = enabled
+		resp := sendGameRequest(t, request, server.URL, "move")
+		resp.Body.Close()
+		header := resp.Header.Get(traceHeader)
+
+		if !enabled {
+			if header != "" {
+				t.Errorf("Expected no trace when disabled, got %s", header)
+			}
+			continue
+		}
+		var events []game.TraceEvent
+		if err := json.Unmarshal([]byte(header), &events); err != nil || len(events) == 0 {
+			t.Fatalf("Expected trace events in header, got %q (%v)", header, err)
+		}
+		if last := events[len(events)-1]; last.Kind != game.TraceChosen || last.Source != "CircleInnerBorder" {
+			t.Errorf("Expected the strategy's choice last, got %+v", last)
+		}
+	}
+	traceMoves = false
+}
+
+func TestEncodeTraceKeepsWithinLimit(t *testing.T) {
+	var events []game.TraceEvent
+	for i := 0; i < 500; i++ {
+		events = append(events, game.TraceEvent{Source: "Minimax", Kind: game.TraceScore, Move: game.SnakeDirection.UP, Reason: fmt.Sprintf("depth %d", i)})
+	}
+	events = append(events, game.TraceEvent{Source: "Minimax", Kind: game.TraceChosen, Move: game.SnakeDirection.LEFT})
+
+	tests := []struct {
+		Name    string
+		Events  []game.TraceEvent
+		Dropped bool
+	}{
+		{"Expect short trace to be kept", events[len(events)-3:], false},
+		{"Expect long trace to be shortened", events, true},
+	}
+
+	for _, tt := range tests {
+		encoded, dropped := encodeTrace(tt.Events, maxTraceHeaderBytes)
+
+		if len(encoded) > maxTraceHeaderBytes {
+			t.Errorf("%s: expected at most %d bytes, got %d", tt.Name, maxTraceHeaderBytes, len(encoded))
+		}
+		if (dropped > 0) != tt.Dropped {
+			t.Errorf("%s: got %d dropped events", tt.Name, dropped)
+		}
+		if kept, _ := json.Marshal(tt.Events[dropped:]); encoded != string(kept) {
+			t.Errorf("%s: expected %s, got %s", tt.Name, kept, encoded)
+		}
+		if dropped > 0 {
+			if longer, _ := json.Marshal(tt.Events[dropped-1:]); len(longer) <= maxTraceHeaderBytes {
+				t.Errorf("%s: expected as many events as fit, got %d of %d", tt.Name, len(tt.Events)-dropped, len(tt.Events))
+			}
+		}
+		var decoded []game.TraceEvent
+		if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+			t.Fatalf("%s: expected valid JSON, got %v", tt.Name, err)
+		}
+		if len(decoded)+dropped != len(tt.Events) || decoded[len(decoded)-1].Kind != game.TraceChosen {
+			t.Errorf("%s: expected the latest events to be kept, got %d of %d", tt.Name, len(decoded), len(tt.Events))
+		}
+	}
+}
+
+func TestMovesAreLoggedWithGameFields(t *testing.T) {
+	var output bytes.Buffer
+	defaultLogger := logging.Default
+	logging.Default = logging.New(&output, logging.LevelDebug, logging.FormatJSON)
+	defer func() { logging.Default = defaultLogger }()
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	request := createGameRequest()
+	request.Game.ID = "logged-game"
+	request.Turn = 4
+	sendGameRequest(t, request, server.URL, "move").Body.Close()
+
+	var line map[string]interface{}
+	if err := json.NewDecoder(&output).Decode(&line); err != nil {
+		t.Fatal(err)
+	}
+	if line["game"] != "logged-game" || line["turn"] != 4.0 || line["snake"] != request.You.ID || line["level"] != "debug" {
+		t.Errorf("Expected a debug line with the game's fields, got %v", line)
+	}
+	if _, ok := line[logging.FieldLatency]; !ok {
+		t.Errorf("Expected the latency to be logged, got %v", line)
+	}
+	if _, ok := line["trace"]; ok {
+		t.Errorf("Expected no trace when disabled, got %v", line)
+	}
+}
+
+func TestTracedMovesAreLoggedOnce(t *testing.T) {
+	var output bytes.Buffer
+	defaultLogger := logging.Default
+	logging.Default = logging.New(&output, logging.LevelInfo, logging.FormatJSON)
+	defer func() { logging.Default = defaultLogger }()
+	traceMoves = true
+	defer func() { traceMoves = false }()
+	server := httptest.NewServer(setupRouter())
+	defer server.Close()
+
+	request := createGameRequest()
+	request.Game.ID = "traced-logged-game"
+	sendGameRequest(t, request, server.URL, "move").Body.Close()
+
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(&output)
+	for decoder.More() {
+		var line map[string]interface{}
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 1 || lines[0]["level"] != "info" || lines[0]["trace"] == nil || lines[0][logging.FieldLatency] == nil {
+		t.Errorf("Expected a single info line with the trace and the latency, got %v", lines)
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+	"github.com/flutter-clutter/starter-snake-go/logging"
+)
+
+// sessionTTL is the time after which a session that has not received any
+// request is considered abandoned (e.g. because the engine never sent /end).
+const sessionTTL = 10 * time.Minute
+
+// session holds the state of our Battlesnake in one particular game.
+type session struct {
+	mu       sync.Mutex
+	snake    *game.StrategicBattlesnake
+	lastSeen time.Time
+}
+
+// sessionStore keeps one session per game and snake, so that the server can
+// play in several games at the same time. It is safe for concurrent use.
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+func newSessionStore(ttl time.Duration) *sessionStore {
+	return &sessionStore{
+		sessions: map[string]*session{},
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+func sessionKey(request GameRequest) string {
+	return request.Game.ID + "/" + request.You.ID
+}
+
+func newStrategicBattlesnake(request GameRequest) *game.StrategicBattlesnake {
+	return &game.StrategicBattlesnake{
+		Snake:    request.You,
+		Action:   game.ApproachBorder{},
+		Strategy: newStrategy(),
+	}
+}
+
+// evaluator scores boards for strategies that search. It is loaded from the
+// weights file named by the WEIGHTS_FILE environment variable, e.g. one
+// written by cmd/tune. Strategies use their default weights if it is nil.
+var evaluator = evaluatorFromEnv("WEIGHTS_FILE")
+
+func evaluatorFromEnv(name string) game.Evaluator {
+	path := os.Getenv(name)
+	if len(path) == 0 {
+		return nil
+	}
+	weights, err := game.LoadWeights(path)
+	if err != nil {
+		logging.Default.Warn("Using default weights: %v", err)
+		return nil
+	}
+	loaded, err := weights.Evaluator()
+	if err != nil {
+		logging.Default.Warn("Using default weights: %v", err)
+		return nil
+	}
+	return loaded
+}
+
+// newStrategy creates the strategy named by the STRATEGY environment variable,
+// CircleInnerBorder by default.
+func newStrategy() game.Strategy {
+	name := os.Getenv("STRATEGY")
+	if len(name) == 0 {
+		return game.CircleInnerBorder{}
+	}
+	strategy, err := game.NewEvaluatedStrategy(name, evaluator)
+	if err != nil {
+		logging.Default.Warn("Falling back to CircleInnerBorder: %v", err)
+		return game.CircleInnerBorder{}
+	}
+	return strategy
+}
+
+// start creates a fresh session for the given request, replacing any existing
+// one. Abandoned sessions are expired on the way.
+func (store *sessionStore) start(request GameRequest) *session {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.expireLocked()
+	s := &session{
+		snake:    newStrategicBattlesnake(request),
+		lastSeen: store.now(),
+	}
+	store.sessions[sessionKey(request)] = s
+	return s
+}
+
+// get returns the session for the given request. If there is none, e.g.
+// because the server was restarted in the middle of a game, a new one is
+// created. Abandoned sessions of other games are expired on the way, so that
+// they don't pile up while no new games start.
+func (store *sessionStore) get(request GameRequest) *session {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	s, ok := store.sessions[sessionKey(request)]
+	if !ok {
+		s = &session{snake: newStrategicBattlesnake(request)}
+		store.sessions[sessionKey(request)] = s
+	}
+	s.lastSeen = store.now()
+	store.expireLocked()
+	return s
+}
+
+// end removes the session for the given request.
+func (store *sessionStore) end(request GameRequest) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.sessions, sessionKey(request))
+}
+
+// expire removes all sessions that have not been used for longer than the
+// store's TTL and returns how many were removed.
+func (store *sessionStore) expire() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.expireLocked()
+}
+
+func (store *sessionStore) expireLocked() int {
+	removed := 0
+	deadline := store.now().Add(-store.ttl)
+	for key, s := range store.sessions {
+		if s.lastSeen.Before(deadline) {
+			delete(store.sessions, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (store *sessionStore) len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return len(store.sessions)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flutter-clutter/starter-snake-go/game"
+)
+
+// spectator receives a drawing of the board on every move of one game, which
+// is set with the SPECTATE environment variable. Nothing is drawn if it is nil.
+var spectator = spectatorFromEnv("SPECTATE")
+
+// clearScreen moves the cursor to the top left of the terminal and clears it.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// spectatorView draws the moves of a single game, as the frames of concurrent
+// games would overwrite each other.
+type spectatorView struct {
+	out io.Writer
+	// filter is the ID of the game to draw. If it is empty, the first game
+	// that moves is drawn until it ends.
+	filter string
+
+	mu     sync.Mutex
+	gameID string
+}
+
+// spectatorFromEnv follows the first game if the variable is true, or the
+// game with the ID it is set to.
+func spectatorFromEnv(name string) *spectatorView {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return nil
+	}
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		if !enabled {
+			return nil
+		}
+		return &spectatorView{out: os.Stdout}
+	}
+	return &spectatorView{out: os.Stdout, filter: value}
+}
+
+// spectate redraws the board of the request together with our move and the
+// action that chose it, if its game is the spectated one.
+func spectate(request GameRequest, move game.SnakeDirectionType, action string, computeTime time.Duration) {
+	if spectator == nil {
+		return
+	}
+	spectator.mu.Lock()
+	defer spectator.mu.Unlock()
+	if !spectator.follows(request.Game.ID) {
+		return
+	}
+	fmt.Fprintf(spectator.out, "%sGame %s, turn %d: %s by %s in %dms\n\n%s",
+		clearScreen, request.Game.ID, request.Turn, move, action, computeTime.Milliseconds(), game.RenderTerminal(request.Board, request.You.ID))
+}
+
+// endSpectating lets the spectator follow the next game once the game it
+// followed without a filter has ended.
+func endSpectating(request GameRequest) {
+	if spectator == nil {
+		return
+	}
+	spectator.mu.Lock()
+	defer spectator.mu.Unlock()
+	if spectator.gameID == request.Game.ID {
+		spectator.gameID = ""
+	}
+}
+
+// follows tells whether the game is drawn, picking it if no game is followed
+// yet. The caller must hold mu.
+func (view *spectatorView) follows(gameID string) bool {
+	if len(view.filter) > 0 {
+		return gameID == view.filter
+	}
+	if len(view.gameID) == 0 {
+		view.gameID = gameID
+	}
+	return gameID == view.gameID
+}